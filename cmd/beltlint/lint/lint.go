@@ -0,0 +1,139 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package lint implements go/analysis analyzers which detect common
+// misuses of the go-belt APIs:
+//
+//   - spanfinish: a started Span is never finished;
+//   - spanctx: the context returned by a function starting a Span is dropped;
+//   - logfformat: a non-constant format string is passed to a Logf-like function;
+//   - metrickey: a metric key is built dynamically (which may result in high cardinality).
+//
+// See command beltlint (github.com/facebookincubator/go-belt/cmd/beltlint)
+// to run them.
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	goBeltPath       = "github.com/facebookincubator/go-belt"
+	tracerPath       = goBeltPath + "/tool/experimental/tracer"
+	metricsTypesPath = goBeltPath + "/tool/experimental/metrics/types"
+)
+
+// Analyzers is the collection of all the analyzers of the package.
+var Analyzers = []*analysis.Analyzer{
+	SpanFinishAnalyzer,
+	SpanCtxAnalyzer,
+	LogfFormatAnalyzer,
+	MetricKeyAnalyzer,
+}
+
+// callee returns the function or method called by the call expression
+// (or nil if it is not a static call, or not a function at all).
+func callee(info *types.Info, call *ast.CallExpr) *types.Func {
+	fn, _ := typeutil.Callee(info, call).(*types.Func)
+	return fn
+}
+
+func isGoBeltFunc(fn *types.Func) bool {
+	if fn == nil || fn.Pkg() == nil {
+		return false
+	}
+	path := fn.Pkg().Path()
+	return path == goBeltPath || strings.HasPrefix(path, goBeltPath+"/")
+}
+
+func isNamedType(t types.Type, pkgPath, name string) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == name && obj.Pkg() != nil && obj.Pkg().Path() == pkgPath
+}
+
+// spanStarter returns the signature of the called function if it is
+// a go-belt function starting a Span (the first result is a tracer.Span).
+func spanStarter(info *types.Info, call *ast.CallExpr) (*types.Func, *types.Signature) {
+	fn := callee(info, call)
+	if !isGoBeltFunc(fn) || !strings.HasPrefix(fn.Name(), "Start") {
+		return nil, nil
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Results().Len() == 0 || !isNamedType(sig.Results().At(0).Type(), tracerPath, "Span") {
+		return nil, nil
+	}
+	return fn, sig
+}
+
+// callName returns a short human-readable name of the called function.
+func callName(fn *types.Func) string {
+	sig, _ := fn.Type().(*types.Signature)
+	if sig != nil && sig.Recv() != nil {
+		recv := sig.Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		if named, ok := recv.(*types.Named); ok {
+			return named.Obj().Name() + "." + fn.Name()
+		}
+		return fn.Name()
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
+
+// assignedTo returns the expressions the results of the call are assigned to,
+// if the call is the only value of an assignment or a variable declaration.
+// It returns nil otherwise.
+func assignedTo(parent ast.Node, call *ast.CallExpr) []ast.Expr {
+	switch parent := parent.(type) {
+	case *ast.AssignStmt:
+		if len(parent.Rhs) == 1 && parent.Rhs[0] == call {
+			return parent.Lhs
+		}
+	case *ast.ValueSpec:
+		if len(parent.Values) == 1 && parent.Values[0] == call {
+			result := make([]ast.Expr, 0, len(parent.Names))
+			for _, name := range parent.Names {
+				result = append(result, name)
+			}
+			return result
+		}
+	}
+	return nil
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// parentOf returns the closest parent node in the stack, skipping parentheses.
+func parentOf(stack []ast.Node) ast.Node {
+	for idx := len(stack) - 2; idx >= 0; idx-- {
+		if _, ok := stack[idx].(*ast.ParenExpr); !ok {
+			return stack[idx]
+		}
+	}
+	return nil
+}
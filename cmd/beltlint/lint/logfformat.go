@@ -0,0 +1,97 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// LogfFormatAnalyzer detects non-constant format strings passed to Logf-like
+// functions (like logger.Debugf or Logger.Logf).
+var LogfFormatAnalyzer = &analysis.Analyzer{
+	Name: "logfformat",
+	Doc: `check that format strings of Logf-like functions are constant
+
+A non-constant format string (for example a message containing a user
+input) is misinterpreted if it contains a '%'. This checker reports calls
+of go-belt functions with a "format string" parameter followed by
+variadic arguments, where the format is not a constant (unless the
+arguments are passed through as "args...").`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runLogfFormat,
+}
+
+func runLogfFormat(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if call.Ellipsis.IsValid() {
+			return
+		}
+		fn := callee(pass.TypesInfo, call)
+		if !isGoBeltFunc(fn) {
+			return
+		}
+		formatIdx := formatParamIndex(fn)
+		if formatIdx < 0 || formatIdx >= len(call.Args) {
+			return
+		}
+		format := call.Args[formatIdx]
+		if pass.TypesInfo.Types[format].Value != nil {
+			return
+		}
+
+		if len(call.Args) > formatIdx+1 {
+			pass.Reportf(format.Pos(), "non-constant format string in call to %s", callName(fn))
+			return
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     format.Pos(),
+			End:     format.End(),
+			Message: "non-constant format string in call to " + callName(fn),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message: `Insert "%s" format string`,
+				TextEdits: []analysis.TextEdit{{
+					Pos:     format.Pos(),
+					End:     format.Pos(),
+					NewText: []byte(strconv.Quote("%s") + ", "),
+				}},
+			}},
+		})
+	})
+	return nil, nil
+}
+
+// formatParamIndex returns the index of parameter "format string" if it
+// is followed by the variadic parameter; otherwise it returns -1.
+func formatParamIndex(fn *types.Func) int {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() || sig.Params().Len() < 2 {
+		return -1
+	}
+	idx := sig.Params().Len() - 2
+	param := sig.Params().At(idx)
+	if param.Name() != "format" {
+		return -1
+	}
+	if basic, ok := param.Type().(*types.Basic); !ok || basic.Kind() != types.String {
+		return -1
+	}
+	return idx
+}
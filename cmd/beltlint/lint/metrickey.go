@@ -0,0 +1,149 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// MetricKeyAnalyzer detects metric keys which are built dynamically.
+var MetricKeyAnalyzer = &analysis.Analyzer{
+	Name: "metrickey",
+	Doc: `check that metric keys are not built dynamically
+
+Each distinct metric key is a separate metric in the backend, thus
+a key containing a variable value (like a user ID or a status code)
+results into an unbounded amount of metrics (high cardinality).
+This checker reports calls of Count, Gauge, IntGauge (and their
+*Fields variants) of go-belt Metrics, where the key is built using
+fmt.Sprint*, strconv, string conversion or concatenation with
+a non-constant value. The variable part should be passed as fields
+(labels) instead.`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runMetricKey,
+}
+
+var metricMethods = map[string]struct{}{
+	"Count":          {},
+	"CountFields":    {},
+	"Gauge":          {},
+	"GaugeFields":    {},
+	"IntGauge":       {},
+	"IntGaugeFields": {},
+}
+
+func runMetricKey(pass *analysis.Pass) (any, error) {
+	ifaces := map[*types.Package]*types.Interface{}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn := callee(pass.TypesInfo, call)
+		if fn == nil || len(call.Args) == 0 {
+			return
+		}
+		if _, ok := metricMethods[fn.Name()]; !ok || fn.Pkg() == nil {
+			return
+		}
+		metricsIface, ok := ifaces[fn.Pkg()]
+		if !ok {
+			// a type implementing Metrics must refer to the package defining it
+			metricsIface = lookupInterface(fn.Pkg(), metricsTypesPath, "Metrics")
+			ifaces[fn.Pkg()] = metricsIface
+		}
+		sig, ok := fn.Type().(*types.Signature)
+		if metricsIface == nil || !ok || sig.Recv() == nil || !types.Implements(sig.Recv().Type(), metricsIface) {
+			return
+		}
+		key := call.Args[0]
+		if reason := dynamicKeyReason(pass.TypesInfo, key); reason != "" {
+			pass.Reportf(key.Pos(), "metric key is built dynamically (%s), which may result in high cardinality; use a constant key and pass the variable part as fields", reason)
+		}
+	})
+	return nil, nil
+}
+
+// dynamicKeyReason returns a description of why the key is considered to be built
+// dynamically, or an empty string if it is not.
+func dynamicKeyReason(info *types.Info, expr ast.Expr) string {
+	expr = ast.Unparen(expr)
+	if info.Types[expr].Value != nil {
+		return ""
+	}
+	switch expr := expr.(type) {
+	case *ast.BinaryExpr:
+		if expr.Op == token.ADD {
+			if info.Types[expr.X].Value == nil || info.Types[expr.Y].Value == nil {
+				return "concatenation with a non-constant value"
+			}
+		}
+	case *ast.CallExpr:
+		if tv := info.Types[expr.Fun]; tv.IsType() {
+			if len(expr.Args) == 1 && !isString(info.TypeOf(expr.Args[0])) {
+				return "conversion to string"
+			}
+			return ""
+		}
+		fn := callee(info, expr)
+		if fn == nil || fn.Pkg() == nil {
+			return ""
+		}
+		switch fn.Pkg().Path() {
+		case "fmt":
+			return "fmt." + fn.Name()
+		case "strconv":
+			return "strconv." + fn.Name()
+		}
+	}
+	return ""
+}
+
+func isString(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// lookupInterface finds the interface type among the (transitive) imports of the package.
+func lookupInterface(pkg *types.Package, path, name string) *types.Interface {
+	visited := map[*types.Package]struct{}{}
+	var find func(pkg *types.Package) *types.Interface
+	find = func(pkg *types.Package) *types.Interface {
+		if _, ok := visited[pkg]; ok {
+			return nil
+		}
+		visited[pkg] = struct{}{}
+		if pkg.Path() == path {
+			obj, _ := pkg.Scope().Lookup(name).(*types.TypeName)
+			if obj == nil {
+				return nil
+			}
+			iface, _ := obj.Type().Underlying().(*types.Interface)
+			return iface
+		}
+		for _, imported := range pkg.Imports() {
+			if iface := find(imported); iface != nil {
+				return iface
+			}
+		}
+		return nil
+	}
+	return find(pkg)
+}
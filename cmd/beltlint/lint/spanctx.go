@@ -0,0 +1,64 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// SpanCtxAnalyzer detects dropped contexts returned by functions starting a Span.
+var SpanCtxAnalyzer = &analysis.Analyzer{
+	Name: "spanctx",
+	Doc: `check that the context returned by a function starting a span is used
+
+Functions like tracer.StartChildSpanFromCtx return a derived context
+which contains the new Span. If it is dropped, then the Spans started
+further are not attached as children of the Span.`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSpanCtx,
+}
+
+func runSpanCtx(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn, sig := spanStarter(pass.TypesInfo, call)
+		if fn == nil || sig.Results().Len() != 2 || !isContext(sig.Results().At(1).Type()) {
+			return true
+		}
+
+		parent := parentOf(stack)
+		if _, ok := parent.(*ast.ExprStmt); ok {
+			pass.Reportf(call.Pos(), "the context returned by %s is dropped", callName(fn))
+			return true
+		}
+		if lhs := assignedTo(parent, call); len(lhs) == 2 && isBlank(lhs[1]) {
+			pass.Reportf(lhs[1].Pos(), "the context returned by %s is dropped, the span is not propagated to the callees", callName(fn))
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// isContext returns true if the type is context.Context or *belt.Belt.
+func isContext(t types.Type) bool {
+	return isNamedType(t, "context", "Context") || isNamedType(t, goBeltPath, "Belt")
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// SpanFinishAnalyzer detects Spans which are started, but never finished
+// (thus never sent).
+var SpanFinishAnalyzer = &analysis.Analyzer{
+	Name: "spanfinish",
+	Doc: `check that started spans are finished
+
+A Span started by a go-belt function (like tracer.StartChildSpanFromCtx
+or Tracer.Start) is sent only when it is finished. This checker reports
+Spans which are discarded or stored to a local variable, which is
+never used to call Finish or FinishWithDuration (and is not passed
+anywhere else).`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSpanFinish,
+}
+
+func runSpanFinish(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn, _ := spanStarter(pass.TypesInfo, call)
+		if fn == nil {
+			return true
+		}
+
+		parent := parentOf(stack)
+		if _, ok := parent.(*ast.ExprStmt); ok {
+			pass.Reportf(call.Pos(), "the span started by %s is discarded and never finished", callName(fn))
+			return true
+		}
+		lhs := assignedTo(parent, call)
+		if len(lhs) == 0 {
+			// the span is used directly (returned, passed as an argument and so on)
+			return true
+		}
+		if isBlank(lhs[0]) {
+			pass.Reportf(call.Pos(), "the span started by %s is assigned to the blank identifier and never finished", callName(fn))
+			return true
+		}
+		ident, ok := lhs[0].(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj := pass.TypesInfo.ObjectOf(ident)
+		body := enclosingFuncBody(stack)
+		if obj == nil || body == nil {
+			return true
+		}
+		if !isSpanHandled(pass.TypesInfo, body, obj) {
+			pass.Reportf(ident.Pos(), "span %s is never finished (call %s.Finish, for example using defer)", ident.Name, ident.Name)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+func enclosingFuncBody(stack []ast.Node) *ast.BlockStmt {
+	for idx := len(stack) - 1; idx >= 0; idx-- {
+		switch node := stack[idx].(type) {
+		case *ast.FuncLit:
+			return node.Body
+		case *ast.FuncDecl:
+			return node.Body
+		}
+	}
+	return nil
+}
+
+// isSpanHandled returns true if the variable is used to finish the span, or if
+// it is used in any way except calling its methods (so it might be finished elsewhere).
+func isSpanHandled(info *types.Info, body *ast.BlockStmt, obj types.Object) bool {
+	finished := false
+	receivers := map[*ast.Ident]struct{}{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := ast.Unparen(sel.X).(*ast.Ident)
+		if !ok || info.Uses[ident] != obj {
+			return true
+		}
+		receivers[ident] = struct{}{}
+		switch sel.Sel.Name {
+		case "Finish", "FinishWithDuration":
+			finished = true
+		}
+		return true
+	})
+	if finished {
+		return true
+	}
+
+	escaped := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || info.Uses[ident] != obj {
+			return !escaped
+		}
+		if _, ok := receivers[ident]; !ok {
+			escaped = true
+		}
+		return !escaped
+	})
+	return escaped
+}
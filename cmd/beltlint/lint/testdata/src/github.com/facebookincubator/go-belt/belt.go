@@ -0,0 +1,3 @@
+package belt
+
+type Belt struct{}
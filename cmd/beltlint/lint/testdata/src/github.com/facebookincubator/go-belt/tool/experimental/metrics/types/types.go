@@ -0,0 +1,10 @@
+package types
+
+type Count interface {
+	Add(v uint64) Count
+}
+
+type Metrics interface {
+	Count(key string) Count
+	CountFields(key string, fields any) Count
+}
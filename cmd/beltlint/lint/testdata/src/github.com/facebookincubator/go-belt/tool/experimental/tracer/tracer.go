@@ -0,0 +1,29 @@
+package tracer
+
+import (
+	"context"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+)
+
+type Span interface {
+	SetField(key string, value any)
+	Finish()
+	FinishWithDuration(duration time.Duration)
+}
+
+type Tracer interface {
+	Start(name string, parent Span) Span
+	StartWithCtx(ctx context.Context, name string) (Span, context.Context)
+}
+
+func FromCtx(ctx context.Context) Tracer { return nil }
+
+func SpanFromCtx(ctx context.Context) Span { return nil }
+
+func StartSpanFromCtx(ctx context.Context, name string) (Span, context.Context) { return nil, ctx }
+
+func StartChildSpanFromCtx(ctx context.Context, name string) (Span, context.Context) { return nil, ctx }
+
+func StartChildSpanFromBelt(belt *belt.Belt, name string) (Span, *belt.Belt) { return nil, belt }
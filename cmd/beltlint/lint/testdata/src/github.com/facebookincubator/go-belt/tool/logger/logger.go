@@ -0,0 +1,19 @@
+package logger
+
+import "context"
+
+type Level int
+
+const LevelInfo = Level(1)
+
+type Logger interface {
+	Logf(level Level, format string, args ...any)
+	Debugf(format string, args ...any)
+	Logt(level Level, template string, args ...any)
+}
+
+func FromCtx(ctx context.Context) Logger { return nil }
+
+func Logf(ctx context.Context, level Level, format string, args ...any) {}
+
+func Debugf(ctx context.Context, format string, args ...any) {}
@@ -0,0 +1,20 @@
+package logfformat
+
+import (
+	"context"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+)
+
+const constFormat = "value: %d"
+
+func calls(ctx context.Context, msg string, args ...any) {
+	logger.Debugf(ctx, "value: %d", 1)
+	logger.Debugf(ctx, constFormat, 1)
+	logger.Debugf(ctx, msg, args...)
+	logger.FromCtx(ctx).Logt(logger.LevelInfo, msg)
+
+	logger.Debugf(ctx, msg)                         // want `non-constant format string in call to logger.Debugf`
+	logger.Logf(ctx, logger.LevelInfo, msg, 1)      // want `non-constant format string in call to logger.Logf`
+	logger.FromCtx(ctx).Logf(logger.LevelInfo, msg) // want `non-constant format string in call to Logger.Logf`
+}
@@ -0,0 +1,26 @@
+package metrickey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/facebookincubator/go-belt/tool/experimental/metrics"
+	"github.com/facebookincubator/go-belt/tool/experimental/metrics/implementation/simplemetrics"
+)
+
+const prefix = "http_"
+
+func calls(ctx context.Context, key string, userID int, raw []byte) {
+	m := metrics.FromCtx(ctx)
+	m.Count("requests")
+	m.Count(prefix + "requests")
+	m.Count(key)
+	m.CountFields("requests", nil)
+
+	m.Count(fmt.Sprintf("requests_%d", userID))    // want `metric key is built dynamically \(fmt.Sprintf\)`
+	m.Count("requests_" + strconv.Itoa(userID))    // want `metric key is built dynamically \(concatenation with a non-constant value\)`
+	m.CountFields(strconv.Itoa(userID), nil)       // want `metric key is built dynamically \(strconv.Itoa\)`
+	m.Count(string(raw))                           // want `metric key is built dynamically \(conversion to string\)`
+	(&simplemetrics.Metrics{}).Count(prefix + key) // want `metric key is built dynamically`
+}
@@ -0,0 +1,27 @@
+package spanctx
+
+import (
+	"context"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/tool/experimental/tracer"
+)
+
+func used(ctx context.Context) context.Context {
+	span, ctx := tracer.StartChildSpanFromCtx(ctx, "used")
+	defer span.Finish()
+	return ctx
+}
+
+func dropped(ctx context.Context, b *belt.Belt) {
+	span, _ := tracer.StartChildSpanFromCtx(ctx, "dropped") // want `the context returned by tracer.StartChildSpanFromCtx is dropped`
+	defer span.Finish()
+
+	tracer.StartSpanFromCtx(ctx, "discarded") // want `the context returned by tracer.StartSpanFromCtx is dropped`
+
+	other, _ := tracer.FromCtx(ctx).StartWithCtx(ctx, "method") // want `the context returned by Tracer.StartWithCtx is dropped`
+	defer other.Finish()
+
+	s, _ := tracer.StartChildSpanFromBelt(b, "belt") // want `the context returned by tracer.StartChildSpanFromBelt is dropped`
+	defer s.Finish()
+}
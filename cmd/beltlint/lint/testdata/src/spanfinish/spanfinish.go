@@ -0,0 +1,58 @@
+package spanfinish
+
+import (
+	"context"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/tool/experimental/tracer"
+)
+
+func finished(ctx context.Context) {
+	span, ctx := tracer.StartChildSpanFromCtx(ctx, "finished")
+	defer span.Finish()
+	_ = ctx
+}
+
+func finishedInClosure(ctx context.Context) {
+	span, _ := tracer.StartChildSpanFromCtx(ctx, "closure")
+	defer func() {
+		span.FinishWithDuration(time.Second)
+	}()
+}
+
+func returned(ctx context.Context) tracer.Span {
+	span, _ := tracer.StartSpanFromCtx(ctx, "returned")
+	span.SetField("key", "value")
+	return span
+}
+
+func passed(ctx context.Context) {
+	span := tracer.FromCtx(ctx).Start("passed", nil)
+	finish(span)
+}
+
+func finish(span tracer.Span) {
+	span.Finish()
+}
+
+func existing(ctx context.Context) {
+	span := tracer.SpanFromCtx(ctx)
+	span.SetField("key", "value")
+}
+
+func notFinished(ctx context.Context) {
+	span, ctx := tracer.StartChildSpanFromCtx(ctx, "not finished") // want `span span is never finished`
+	span.SetField("key", "value")
+	_ = ctx
+}
+
+func notFinishedBelt(b *belt.Belt) {
+	var s, _ = tracer.StartChildSpanFromBelt(b, "not finished") // want `span s is never finished`
+	s.SetField("key", "value")
+}
+
+func discarded(ctx context.Context) {
+	tracer.FromCtx(ctx).Start("discarded", nil)  // want `the span started by Tracer.Start is discarded`
+	_, _ = tracer.StartSpanFromCtx(ctx, "blank") // want `the span started by tracer.StartSpanFromCtx is assigned to the blank identifier`
+}
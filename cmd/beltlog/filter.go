@@ -0,0 +1,143 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+type fieldOp uint
+
+const (
+	fieldOpEqual = fieldOp(iota)
+	fieldOpNotEqual
+	fieldOpMatch
+)
+
+// fieldExpr is a condition on a field value: "key=value", "key!=value" or "key~regexp".
+type fieldExpr struct {
+	Key    field.Key
+	Op     fieldOp
+	Value  string
+	Regexp *regexp.Regexp
+}
+
+func parseFieldExpr(s string) (fieldExpr, error) {
+	idx := strings.IndexAny(s, "=~")
+	if idx <= 0 {
+		return fieldExpr{}, fmt.Errorf("invalid field expression '%s', expected key=value, key!=value or key~regexp", s)
+	}
+	expr := fieldExpr{
+		Key:   s[:idx],
+		Value: s[idx+1:],
+	}
+	switch {
+	case s[idx] == '~':
+		expr.Op = fieldOpMatch
+		re, err := regexp.Compile(expr.Value)
+		if err != nil {
+			return fieldExpr{}, fmt.Errorf("invalid regexp in field expression '%s': %w", s, err)
+		}
+		expr.Regexp = re
+	case strings.HasSuffix(expr.Key, "!"):
+		expr.Op = fieldOpNotEqual
+		expr.Key = strings.TrimSuffix(expr.Key, "!")
+	}
+	if expr.Key == "" {
+		return fieldExpr{}, fmt.Errorf("invalid field expression '%s': empty key", s)
+	}
+	return expr, nil
+}
+
+func (expr fieldExpr) String() string {
+	switch expr.Op {
+	case fieldOpNotEqual:
+		return expr.Key + "!=" + expr.Value
+	case fieldOpMatch:
+		return expr.Key + "~" + expr.Value
+	}
+	return expr.Key + "=" + expr.Value
+}
+
+// Match returns true if the fields satisfy the condition. A missing
+// field satisfies only the "!=" condition.
+func (expr fieldExpr) Match(fields field.AbstractFields) bool {
+	found := false
+	matched := false
+	if fields != nil {
+		fields.ForEachField(func(f *field.Field) bool {
+			if f.Key != expr.Key {
+				return true
+			}
+			found = true
+			value := fmt.Sprint(f.Value)
+			switch expr.Op {
+			case fieldOpMatch:
+				matched = expr.Regexp.MatchString(value)
+			default:
+				matched = value == expr.Value
+			}
+			return false
+		})
+	}
+	if expr.Op == fieldOpNotEqual {
+		return !found || !matched
+	}
+	return matched
+}
+
+// filter defines which entries should be shown.
+type filter struct {
+	Level   types.Level
+	Since   time.Time
+	Until   time.Time
+	TraceID belt.TraceID
+	Fields  []fieldExpr
+}
+
+// IsEmpty returns true if the filter matches any entry.
+func (f *filter) IsEmpty() bool {
+	return f.Level == types.LevelUndefined && f.Since.IsZero() && f.Until.IsZero() && f.TraceID == "" && len(f.Fields) == 0
+}
+
+// Match returns true if the entry should be shown.
+func (f *filter) Match(entry *types.Entry) bool {
+	if !types.MatchLevelAndTime(entry.Level, entry.Timestamp, f.Level, f.Since, f.Until) {
+		return false
+	}
+	if f.TraceID != "" && !hasTraceID(entry.TraceIDs, f.TraceID) {
+		return false
+	}
+	for _, expr := range f.Fields {
+		if !expr.Match(entry.Fields) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTraceID(traceIDs belt.TraceIDs, traceID belt.TraceID) bool {
+	for _, cmp := range traceIDs {
+		if cmp == traceID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldExpr(t *testing.T) {
+	expr, err := parseFieldExpr("user_id=42")
+	require.NoError(t, err)
+	require.Equal(t, fieldExpr{Key: "user_id", Op: fieldOpEqual, Value: "42"}, expr)
+
+	expr, err = parseFieldExpr("user_id!=42")
+	require.NoError(t, err)
+	require.Equal(t, fieldExpr{Key: "user_id", Op: fieldOpNotEqual, Value: "42"}, expr)
+
+	expr, err = parseFieldExpr("path~^/api/")
+	require.NoError(t, err)
+	require.Equal(t, fieldOpMatch, expr.Op)
+	require.Equal(t, "path~^/api/", expr.String())
+
+	for _, in := range []string{"user_id", "=42", "!=42", "path~("} {
+		_, err := parseFieldExpr(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	ts := time.Date(2022, 2, 24, 1, 0, 0, 0, time.UTC)
+	entry := &types.Entry{
+		Timestamp: ts,
+		Level:     types.LevelWarning,
+		Fields: field.Fields{
+			{Key: "user_id", Value: int64(42)},
+			{Key: "path", Value: "/api/v1/users"},
+		},
+		TraceIDs: belt.TraceIDs{"a", "b"},
+	}
+
+	mustParse := func(s string) fieldExpr {
+		expr, err := parseFieldExpr(s)
+		require.NoError(t, err)
+		return expr
+	}
+
+	for name, tc := range map[string]struct {
+		Filter   filter
+		Expected bool
+	}{
+		"empty":           {filter{}, true},
+		"level_ok":        {filter{Level: types.LevelWarning}, true},
+		"level_too_low":   {filter{Level: types.LevelError}, false},
+		"since_ok":        {filter{Since: ts}, true},
+		"since_too_late":  {filter{Since: ts.Add(time.Second)}, false},
+		"until_exclusive": {filter{Until: ts}, false},
+		"trace_ok":        {filter{TraceID: "b"}, true},
+		"trace_missing":   {filter{TraceID: "c"}, false},
+		"field_equal":     {filter{Fields: []fieldExpr{mustParse("user_id=42")}}, true},
+		"field_not_equal": {filter{Fields: []fieldExpr{mustParse("user_id!=42")}}, false},
+		"field_absent":    {filter{Fields: []fieldExpr{mustParse("group_id!=1")}}, true},
+		"field_regexp":    {filter{Fields: []fieldExpr{mustParse("path~^/api/")}}, true},
+		"field_missing":   {filter{Fields: []fieldExpr{mustParse("group_id=1")}}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, tc.Filter.Match(entry))
+		})
+	}
+}
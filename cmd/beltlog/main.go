@@ -0,0 +1,200 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Command beltlog reads JSON or logfmt logs (for example produced by the zap
+// or logrus implementations of the go-belt Logger), filters them and
+// pretty-prints them like a development console.
+//
+// Usage examples:
+//
+//	beltlog -level warning app.log
+//	beltlog -f -where user_id=42 -where 'path~^/api/' app.log
+//	beltlog -since 15m -until 5m app.log
+//	beltlog -follow-trace 3f2a9c0e frontend.log backend.log worker.log
+//	kubectl logs my-pod | beltlog -trace 3f2a9c0e
+//
+// If no files are given (or the file name is "-"), the standard input is read.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/tool/logger/replay"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+type fieldExprsFlag []fieldExpr
+
+func (f *fieldExprsFlag) String() string {
+	var result []string
+	for _, expr := range *f {
+		result = append(result, expr.String())
+	}
+	return strings.Join(result, ",")
+}
+
+func (f *fieldExprsFlag) Set(value string) error {
+	expr, err := parseFieldExpr(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, expr)
+	return nil
+}
+
+type timeFlag struct {
+	time.Time
+}
+
+func (f *timeFlag) String() string {
+	if f.IsZero() {
+		return ""
+	}
+	return f.Format(time.RFC3339)
+}
+
+func (f *timeFlag) Set(value string) error {
+	ts, err := parseTime(value, time.Now())
+	if err != nil {
+		return err
+	}
+	f.Time = ts
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file ...]\n", filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
+}
+
+func main() {
+	var (
+		filter     filter
+		fieldExprs fieldExprsFlag
+		since      timeFlag
+		until      timeFlag
+	)
+	formatFlag := flag.String("format", "auto", "format of the logs: json, logfmt or auto")
+	levelFlag := flag.String("level", "", "show only entries of this level or more severe ones")
+	traceIDFlag := flag.String("trace", "", "show only entries with this trace ID")
+	followTraceFlag := flag.String("follow-trace", "", "collect entries with this trace ID from all the files and show them ordered by time")
+	followFlag := flag.Bool("f", false, "show the entries appended to the files as they grow (like 'tail -f')")
+	colorFlag := flag.String("color", "auto", "colorize the output: always, never or auto")
+	flag.Var(&fieldExprs, "where", "show only entries with a matching field: key=value, key!=value or key~regexp; may be repeated")
+	flag.Var(&since, "since", "show only entries since this time: RFC3339 time or a duration ago (for example 15m)")
+	flag.Var(&until, "until", "show only entries before this time: RFC3339 time or a duration ago (for example 5m)")
+	flag.Usage = usage
+	flag.Parse()
+
+	format, err := parseFormat(*formatFlag)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if *levelFlag != "" {
+		filter.Level, err = types.ParseLogLevel(*levelFlag)
+		if err != nil {
+			fatalf("%v", err)
+		}
+	}
+	filter.TraceID = belt.TraceID(*traceIDFlag)
+	filter.Fields = fieldExprs
+	filter.Since = since.Time
+	filter.Until = until.Time
+
+	p := &printer{Out: os.Stdout}
+	switch *colorFlag {
+	case "always":
+		p.Color = true
+	case "never":
+	case "auto":
+		p.Color = isTerminal(os.Stdout)
+	default:
+		fatalf("unknown color mode '%s'", *colorFlag)
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	p.ShowSource = len(files) > 1
+
+	ctx := context.Background()
+	if *followFlag {
+		var cancel context.CancelFunc
+		ctx, cancel = signal.NotifyContext(ctx, os.Interrupt)
+		defer cancel()
+	}
+
+	v := &viewer{
+		Format:  format,
+		Filter:  filter,
+		Printer: p,
+	}
+	switch {
+	case *followTraceFlag != "":
+		if *followFlag {
+			fatalf("flags -f and -follow-trace are mutually exclusive")
+		}
+		v.Filter.TraceID = belt.TraceID(*followTraceFlag)
+		err = v.FollowTrace(ctx, files)
+	default:
+		err = v.View(ctx, files, *followFlag)
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func parseFormat(s string) (replay.Format, error) {
+	switch s {
+	case "json":
+		return replay.FormatJSON, nil
+	case "logfmt":
+		return replay.FormatLogfmt, nil
+	case "auto":
+		return replay.FormatAuto, nil
+	}
+	return 0, fmt.Errorf("unknown format '%s'", s)
+}
+
+// parseTime parses either an RFC3339 time or a duration ago.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return ts, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is neither an RFC3339 time nor a duration", s)
+	}
+	return now.Add(-d), nil
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "beltlog: "+format+"\n", args...)
+	os.Exit(1)
+}
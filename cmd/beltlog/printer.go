@@ -0,0 +1,150 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/hooks/sanitizer"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorGray   = "\x1b[90m"
+	colorBold   = "\x1b[1m"
+)
+
+const timestampLayout = "2006-01-02 15:04:05.000"
+
+// printer writes entries in a human-readable form:
+//
+//	2022-02-24 01:02:03.456 WARN  disk is almost full  free_mb=42 trace_id=3f2a9c0e
+//
+// Control characters are escaped (see sanitizer.Escape), so that a log
+// cannot inject terminal escape sequences.
+type printer struct {
+	Out        io.Writer
+	Color      bool
+	ShowSource bool
+
+	locker sync.Mutex
+}
+
+// Print writes the entry. Argument "source" is the name of the file the entry was read from.
+func (p *printer) Print(source string, entry *types.Entry) {
+	var buf strings.Builder
+	if p.ShowSource {
+		p.colored(&buf, colorGray, sanitizer.Escape(source)+" ")
+	}
+	if entry.Timestamp.IsZero() {
+		p.colored(&buf, colorGray, fmt.Sprintf("%-*s", len(timestampLayout), "-"))
+	} else {
+		p.colored(&buf, colorGray, entry.Timestamp.Format(timestampLayout))
+	}
+	buf.WriteByte(' ')
+	p.colored(&buf, levelColor(entry.Level), fmt.Sprintf("%-5s", levelName(entry.Level)))
+	buf.WriteByte(' ')
+	p.colored(&buf, colorBold, sanitizer.Escape(entry.Message))
+
+	if entry.Fields != nil {
+		if entry.Fields.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			buf.WriteByte(' ')
+			p.colored(&buf, colorBlue, sanitizer.Escape(f.Key)+"=")
+			buf.WriteString(formatValue(f.Value))
+			return true
+		})
+	}
+	if len(entry.TraceIDs) > 0 {
+		traceIDs := make([]string, 0, len(entry.TraceIDs))
+		for _, traceID := range entry.TraceIDs {
+			traceIDs = append(traceIDs, sanitizer.Escape(string(traceID)))
+		}
+		buf.WriteByte(' ')
+		p.colored(&buf, colorBlue, "trace_id=")
+		buf.WriteString(strings.Join(traceIDs, ","))
+	}
+	buf.WriteByte('\n')
+	p.write(buf.String())
+}
+
+// PrintRaw writes a line which could not be parsed.
+func (p *printer) PrintRaw(source string, line string) {
+	var buf strings.Builder
+	if p.ShowSource {
+		p.colored(&buf, colorGray, sanitizer.Escape(source)+" ")
+	}
+	p.colored(&buf, colorGray, sanitizer.Escape(line))
+	buf.WriteByte('\n')
+	p.write(buf.String())
+}
+
+func (p *printer) write(s string) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	_, _ = io.WriteString(p.Out, s)
+}
+
+func (p *printer) colored(buf *strings.Builder, color string, s string) {
+	if !p.Color {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(colorReset)
+}
+
+func levelName(level types.Level) string {
+	switch level {
+	case types.LevelUndefined:
+		return "-"
+	case types.LevelWarning:
+		return "WARN"
+	}
+	return strings.ToUpper(level.String())
+}
+
+func levelColor(level types.Level) string {
+	switch {
+	case level <= types.LevelNone:
+		return colorGray
+	case level <= types.LevelError:
+		return colorRed
+	case level <= types.LevelWarning:
+		return colorYellow
+	case level <= types.LevelInfo:
+		return colorGreen
+	}
+	return colorGray
+}
+
+func formatValue(value any) string {
+	s := fmt.Sprint(value)
+	if s == "" || strings.ContainsAny(s, " \t\"=") || strconv.Quote(s) != `"`+s+`"` {
+		return strconv.Quote(s)
+	}
+	return s
+}
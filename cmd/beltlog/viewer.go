@@ -0,0 +1,177 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger/replay"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// followPollInterval is the interval of checking if a followed file has grown.
+const followPollInterval = 200 * time.Millisecond
+
+type viewer struct {
+	Format  replay.Format
+	Filter  filter
+	Printer *printer
+}
+
+// View prints the matching entries of the files (one after another, or concurrently if "follow" is true).
+func (v *viewer) View(ctx context.Context, files []string, follow bool) error {
+	if !follow {
+		for _, name := range files {
+			if err := v.readFile(ctx, name, false, v.print); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for _, name := range files {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if err := v.readFile(ctx, name, true, v.print); err != nil {
+				errOnce.Do(func() { firstErr = err })
+			}
+		}(name)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// FollowTrace collects the matching entries of all the files and prints
+// them ordered by timestamp.
+func (v *viewer) FollowTrace(ctx context.Context, files []string) error {
+	type sourcedEntry struct {
+		Source string
+		Entry  *types.Entry
+	}
+	var entries []sourcedEntry
+	for _, name := range files {
+		err := v.readFile(ctx, name, false, func(source string, entry *types.Entry) {
+			entries = append(entries, sourcedEntry{Source: source, Entry: entry})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Entry.Timestamp.Before(entries[j].Entry.Timestamp)
+	})
+	for _, item := range entries {
+		v.Printer.Print(item.Source, item.Entry)
+	}
+	return nil
+}
+
+func (v *viewer) print(source string, entry *types.Entry) {
+	v.Printer.Print(source, entry)
+}
+
+func (v *viewer) readFile(
+	ctx context.Context,
+	name string,
+	follow bool,
+	callback func(source string, entry *types.Entry),
+) error {
+	var in io.Reader
+	switch name {
+	case "-":
+		name = "<stdin>"
+		in = os.Stdin
+	default:
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+		if follow {
+			// like 'tail -f', showing only the entries appended since now
+			if _, err := f.Seek(0, io.SeekEnd); err != nil {
+				return err
+			}
+			in = &followReader{Context: ctx, File: f}
+		}
+	}
+
+	reader := replay.NewReader(in, replay.OptionFormat(v.Format))
+	for {
+		entry, err := reader.Read()
+		if err != nil {
+			var parseErr *replay.ParseError
+			switch {
+			case errors.Is(err, io.EOF):
+				return nil
+			case errors.As(err, &parseErr):
+				if v.Filter.IsEmpty() {
+					v.Printer.PrintRaw(name, parseErr.Text)
+				}
+				continue
+			}
+			return fmt.Errorf("unable to read '%s': %w", name, err)
+		}
+		if v.Filter.Match(entry) {
+			callback(name, entry)
+		}
+	}
+}
+
+// followReader is an io.Reader which waits for new data on the end of
+// the file (instead of returning io.EOF) until the context is done.
+type followReader struct {
+	Context context.Context
+	File    *os.File
+}
+
+func (r *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.File.Read(p)
+		if n > 0 || (err != nil && !errors.Is(err, io.EOF)) {
+			return n, err
+		}
+
+		offset, err := r.File.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		// if the file was truncated (for example by a log rotation), then starting over
+		if stat, err := r.File.Stat(); err == nil && stat.Size() < offset {
+			if _, err := r.File.Seek(0, io.SeekStart); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		select {
+		case <-r.Context.Done():
+			return 0, io.EOF
+		case <-time.After(followPollInterval):
+		}
+	}
+}
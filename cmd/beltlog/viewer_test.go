@@ -0,0 +1,143 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger/replay"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewerFollowTrace(t *testing.T) {
+	dir := t.TempDir()
+	frontend := filepath.Join(dir, "frontend.log")
+	backend := filepath.Join(dir, "backend.log")
+	require.NoError(t, os.WriteFile(frontend, []byte(
+		`{"level":"info","ts":"2022-02-24T01:00:00Z","msg":"request received","trace_id":["t1"],"path":"/api"}
+{"level":"info","ts":"2022-02-24T01:00:05Z","msg":"another request","trace_id":["t2"]}
+{"level":"info","ts":"2022-02-24T01:00:03Z","msg":"response sent","trace_id":["t1"]}
+`), 0644))
+	require.NoError(t, os.WriteFile(backend, []byte(
+		`time="2022-02-24T01:00:01Z" level=warning msg="slow query" trace_id="[t1]" took=2s
+garbage
+`), 0644))
+
+	var out bytes.Buffer
+	v := &viewer{
+		Format:  replay.FormatAuto,
+		Filter:  filter{TraceID: "t1"},
+		Printer: &printer{Out: &out},
+	}
+	require.NoError(t, v.FollowTrace(context.Background(), []string{frontend, backend}))
+	require.Equal(t, `2022-02-24 01:00:00.000 INFO  request received  path=/api trace_id=t1
+2022-02-24 01:00:01.000 WARN  slow query  took=2s trace_id=t1
+2022-02-24 01:00:03.000 INFO  response sent trace_id=t1
+`, out.String())
+}
+
+func TestViewerView(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(file, []byte(
+		`{"level":"debug","msg":"hello"}
+plain text
+{"level":"error","msg":"oops","error":"EOF"}
+`), 0644))
+
+	var out bytes.Buffer
+	v := &viewer{
+		Format:  replay.FormatAuto,
+		Printer: &printer{Out: &out},
+	}
+	require.NoError(t, v.View(context.Background(), []string{file}, false))
+	require.Equal(t, `-                       DEBUG hello
+plain text
+-                       ERROR oops  error=EOF
+`, out.String())
+}
+
+func TestViewerViewEscapesControlCharacters(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(file, []byte(
+		`{"level":"info","msg":"hello\u001b[2J\nFAKE","user\u001b":"x\u001by"}
+raw \x1b[31mtext
+`), 0644))
+
+	var out bytes.Buffer
+	v := &viewer{
+		Format:  replay.FormatAuto,
+		Printer: &printer{Out: &out},
+	}
+	require.NoError(t, v.View(context.Background(), []string{file}, false))
+	require.NotContains(t, out.String(), "\x1b")
+	require.Equal(t, `-                       INFO  hello\x1b[2J\nFAKE  user\x1b="x\x1by"
+raw \x1b[31mtext
+`, out.String())
+}
+
+type syncBuffer struct {
+	locker sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	return b.buf.String()
+}
+
+func TestViewerViewFollowStartsFromTheEnd(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(file, []byte(`{"level":"info","msg":"old"}
+`), 0644))
+
+	var out syncBuffer
+	v := &viewer{
+		Format:  replay.FormatAuto,
+		Printer: &printer{Out: &out},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- v.View(ctx, []string{file}, true)
+	}()
+
+	// giving the viewer the time to open the file
+	time.Sleep(followPollInterval)
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"level":"info","msg":"new"}
+`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "new")
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, "-                       INFO  new\n", out.String())
+}
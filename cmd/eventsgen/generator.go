@@ -0,0 +1,187 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+const generatedHeader = "// Code generated by eventsgen; DO NOT EDIT."
+
+// levelIdentifiers maps the supported levels to their identifiers in package logger.
+var levelIdentifiers = map[types.Level]string{
+	types.LevelFatal:   "LevelFatal",
+	types.LevelPanic:   "LevelPanic",
+	types.LevelError:   "LevelError",
+	types.LevelWarning: "LevelWarning",
+	types.LevelInfo:    "LevelInfo",
+	types.LevelDebug:   "LevelDebug",
+	types.LevelTrace:   "LevelTrace",
+}
+
+type generator struct {
+	buf     bytes.Buffer
+	imports map[string]struct{}
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// Generate generates the source code of package `packageName` with
+// a logging function for each event of the schema, and registers the
+// schema in package eventschema.
+func Generate(schema *Schema, packageName string) ([]byte, error) {
+	g := &generator{
+		imports: map[string]struct{}{
+			"context": {},
+			"github.com/facebookincubator/go-belt/pkg/field":               {},
+			"github.com/facebookincubator/go-belt/tool/logger":             {},
+			"github.com/facebookincubator/go-belt/tool/logger/eventschema": {},
+		},
+	}
+
+	g.printf("func init() {\n")
+	g.printf("eventschema.MustRegister(\n")
+	for idx := range schema.Events {
+		g.generateSchema(&schema.Events[idx])
+	}
+	g.printf(")\n")
+	g.printf("}\n")
+
+	for idx := range schema.Events {
+		g.generateFunc(&schema.Events[idx])
+	}
+
+	return g.source(packageName)
+}
+
+func (g *generator) generateSchema(ev *Event) {
+	g.printf("eventschema.Event{\n")
+	g.printf("Name: %s,\n", strconv.Quote(ev.Name))
+	g.printf("Level: logger.%s,\n", g.levelIdentifier(ev.Level))
+	g.printf("Message: %s,\n", strconv.Quote(ev.Message))
+	if ev.Doc != "" {
+		g.printf("Doc: %s,\n", strconv.Quote(strings.TrimSpace(ev.Doc)))
+	}
+	if len(ev.Fields) != 0 {
+		g.printf("Fields: []eventschema.Field{\n")
+		for _, f := range ev.Fields {
+			g.printf("{Key: %s, Type: %s", strconv.Quote(f.Key), strconv.Quote(f.Type))
+			if f.Doc != "" {
+				g.printf(", Doc: %s", strconv.Quote(strings.TrimSpace(f.Doc)))
+			}
+			g.printf("},\n")
+		}
+		g.printf("},\n")
+	}
+	g.printf("},\n")
+}
+
+func (g *generator) generateFunc(ev *Event) {
+	g.printf("\n// %s logs event %s (level %s).\n", ev.Name, strconv.Quote(ev.Name), strconv.Quote(ev.Level))
+	if ev.Doc != "" {
+		g.printf("//\n")
+		g.printComment(ev.Doc, "")
+	}
+	if len(ev.Fields) != 0 {
+		g.printf("//\n// Fields:\n")
+		for _, f := range ev.Fields {
+			g.printf("//   - %s (%s)", f.Key, f.Type)
+			if f.Doc != "" {
+				g.printf(":")
+				g.printComment(f.Doc, " ")
+			} else {
+				g.printf("\n")
+			}
+		}
+	}
+
+	args := make([]string, 0, len(ev.Fields)+1)
+	args = append(args, "ctx context.Context")
+	for _, f := range ev.Fields {
+		args = append(args, f.Name+" "+f.Type)
+		if importPath := supportedTypes[f.Type]; importPath != "" {
+			g.imports[importPath] = struct{}{}
+		}
+	}
+	g.printf("func %s(%s) {\n", ev.Name, strings.Join(args, ", "))
+	g.printf("logger.LogFields(ctx, logger.%s, %s, field.Fields{\n", g.levelIdentifier(ev.Level), strconv.Quote(ev.Message))
+	g.printf("{Key: eventschema.EventKey, Value: %s},\n", strconv.Quote(ev.Name))
+	for _, f := range ev.Fields {
+		g.printf("{Key: %s, Value: %s},\n", strconv.Quote(f.Key), f.Name)
+	}
+	g.printf("})\n")
+	g.printf("}\n")
+}
+
+// printComment prints a (possibly multiline) text as a comment. The first
+// line is appended to the current line using the separator `sep`, unless
+// `sep` is empty.
+func (g *generator) printComment(text string, sep string) {
+	for idx, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimRight(line, " \t")
+		switch {
+		case idx == 0 && sep != "":
+			g.printf("%s%s\n", sep, line)
+		case sep != "":
+			g.printf("//     %s\n", line)
+		case line == "":
+			g.printf("//\n")
+		default:
+			g.printf("// %s\n", line)
+		}
+	}
+}
+
+func (g *generator) levelIdentifier(levelName string) string {
+	level, _ := types.ParseLogLevel(levelName)
+	return levelIdentifiers[level]
+}
+
+func (g *generator) source(packageName string) ([]byte, error) {
+	var result bytes.Buffer
+	fmt.Fprintf(&result, "%s\n\npackage %s\n\nimport (\n", generatedHeader, packageName)
+	var stdImportPaths, importPaths []string
+	for importPath := range g.imports {
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			importPaths = append(importPaths, importPath)
+		} else {
+			stdImportPaths = append(stdImportPaths, importPath)
+		}
+	}
+	sort.Strings(stdImportPaths)
+	sort.Strings(importPaths)
+	for _, importPath := range stdImportPaths {
+		fmt.Fprintf(&result, "%s\n", strconv.Quote(importPath))
+	}
+	result.WriteString("\n")
+	for _, importPath := range importPaths {
+		fmt.Fprintf(&result, "%s\n", strconv.Quote(importPath))
+	}
+	result.WriteString(")\n\n")
+	result.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(result.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format the generated code: %w\n%s", err, result.Bytes())
+	}
+	return formatted, nil
+}
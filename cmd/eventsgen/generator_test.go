@@ -0,0 +1,77 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "logevents")
+	schema, err := LoadSchema(filepath.Join(dir, "schema.yaml"))
+	require.NoError(t, err)
+	source, err := Generate(schema, schema.Package)
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile(filepath.Join(dir, "schema_events.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(source), "run 'go generate ./...' in %s", dir)
+}
+
+func TestParseSchemaJSON(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{"events": [{"name": "HTTPRequestServed", "level": "warn", "fields": [{"name": "statusCode", "type": "int"}]}]}`))
+	require.NoError(t, err)
+	require.Equal(t, []Event{{
+		Name:    "HTTPRequestServed",
+		Level:   "warning",
+		Message: "http request served",
+		Fields:  []Field{{Name: "statusCode", Key: "status_code", Type: "int"}},
+	}}, schema.Events)
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	for name, schema := range map[string]string{
+		"no events":        `events: []`,
+		"unexported name":  `events: [{name: userLoggedIn}]`,
+		"duplicate event":  `events: [{name: A}, {name: A}]`,
+		"unknown level":    `events: [{name: A, level: loud}]`,
+		"unsupported type": `events: [{name: A, fields: [{name: x, type: chan int}]}]`,
+		"keyword argument": `events: [{name: A, fields: [{name: type, type: string}]}]`,
+		"import argument":  `events: [{name: A, fields: [{name: logger, type: string}]}]`,
+		"type argument":    `events: [{name: A, fields: [{name: string, type: string}]}]`,
+		"time argument":    `events: [{name: A, fields: [{name: time, type: int}]}]`,
+		"duplicate key":    `events: [{name: A, fields: [{name: a, type: int}, {name: b, key: a, type: int}]}]`,
+		"reserved key":     `events: [{name: A, fields: [{name: event, type: string}]}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSchema([]byte(schema))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, out := range map[string]string{
+		"userID":       "user_id",
+		"HTTPMethod":   "http_method",
+		"user_id":      "user_id",
+		"retry2Count":  "retry2_count",
+		"UserLoggedIn": "user_logged_in",
+	} {
+		require.Equal(t, out, snakeCase(in), in)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logevents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/eventschema"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyEmitter struct {
+	entries []types.Entry
+}
+
+func (e *dummyEmitter) Flush() {}
+func (e *dummyEmitter) Emit(entry *types.Entry) {
+	cpy := *entry
+	cpy.Fields = field.Gather(entry.Fields)
+	e.entries = append(e.entries, cpy)
+}
+
+func TestGeneratedFunc(t *testing.T) {
+	var emitter dummyEmitter
+	ctx := logger.CtxWithLogger(context.Background(), adapter.LoggerFromEmitter(&emitter).WithLevel(types.LevelInfo))
+
+	UserLoggedIn(ctx, 42, "password")
+	CacheFlushed(ctx)
+
+	require.Len(t, emitter.entries, 1)
+	entry := emitter.entries[0]
+	require.Equal(t, types.LevelInfo, entry.Level)
+	require.Equal(t, "user logged in", entry.Message)
+	require.Equal(t, field.Fields{
+		{Key: eventschema.EventKey, Value: "UserLoggedIn"},
+		{Key: "user_id", Value: int64(42)},
+		{Key: "method", Value: "password"},
+	}, entry.Fields)
+
+	schema, ok := eventschema.FromEntry(&entry)
+	require.True(t, ok)
+	require.Equal(t, "UserLoggedIn", schema.Name)
+	require.Equal(t, []eventschema.Field{
+		{Key: "user_id", Type: "int64", Doc: "The ID of the user."},
+		{Key: "method", Type: "string", Doc: "The authentication method,\nfor example \"password\" or \"oauth\"."},
+	}, schema.Fields)
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Command eventsgen generates typed logging functions from a schema of
+// events, so that the logged events have guaranteed shapes.
+//
+// The schema is a YAML (or JSON) file, for example:
+//
+//	package: logevents
+//	events:
+//	  - name: UserLoggedIn
+//	    level: info
+//	    doc: A user has successfully logged in.
+//	    fields:
+//	      - name: userID
+//	        type: int64
+//	      - name: method
+//	        type: string
+//	        doc: The authentication method.
+//
+// For each event a function is generated, which logs the event through
+// logger.LogFields with prebuilt fields (including field "event" with
+// the name of the event):
+//
+//	func UserLoggedIn(ctx context.Context, userID int64, method string)
+//
+// Optional values have defaults: the level is "info", the message is
+// derived from the name ("user logged in"), the key of a field is derived
+// from the name of the field ("user_id"). The generated code also registers
+// the schema in package eventschema for runtime introspection.
+//
+// Usage:
+//
+//	//go:generate go run github.com/facebookincubator/go-belt/cmd/eventsgen events.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <schema.yaml|schema.json>\n", filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
+}
+
+func main() {
+	packageFlag := flag.String("package", "", "package name; default: the package from the schema or the name of the output directory")
+	outputFlag := flag.String("output", "", "output file name; default: <schema path without extension>_events.go")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	schemaPath := flag.Arg(0)
+
+	schema, err := LoadSchema(schemaPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eventsgen: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputPath := *outputFlag
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(schemaPath, filepath.Ext(schemaPath)) + "_events.go"
+	}
+
+	packageName := *packageFlag
+	if packageName == "" {
+		packageName = schema.Package
+	}
+	if packageName == "" {
+		absOutputPath, err := filepath.Abs(outputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "eventsgen: %v\n", err)
+			os.Exit(1)
+		}
+		packageName = filepath.Base(filepath.Dir(absOutputPath))
+	}
+
+	source, err := Generate(schema, packageName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eventsgen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(outputPath, source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "eventsgen: unable to write '%s': %v\n", outputPath, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/facebookincubator/go-belt/tool/logger/eventschema"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a collection of events to generate the logging functions for.
+type Schema struct {
+	// Package is the name of the generated package (optional).
+	Package string `yaml:"package"`
+
+	// Events are the definitions of the events.
+	Events []Event `yaml:"events"`
+}
+
+// Event is a definition of an event.
+type Event struct {
+	// Name is the name of the event and of the generated function (an exported Go identifier).
+	Name string `yaml:"name"`
+
+	// Level is the logging level of the event ("info" by default).
+	Level string `yaml:"level"`
+
+	// Message is the message of the event (by default it is derived from Name,
+	// for example "user logged in" for "UserLoggedIn").
+	Message string `yaml:"message"`
+
+	// Doc is the documentation of the event.
+	Doc string `yaml:"doc"`
+
+	// Fields are the definitions of the fields of the event.
+	Fields []Field `yaml:"fields"`
+}
+
+// Field is a definition of a field of an event.
+type Field struct {
+	// Name is the name of the argument of the generated function. It could
+	// not be an identifier referred by the generated code (see reservedNames).
+	Name string `yaml:"name"`
+
+	// Key is the key of the field (by default it is derived from Name,
+	// for example "user_id" for "userID").
+	Key string `yaml:"key"`
+
+	// Type is the Go type of the value (see supportedTypes).
+	Type string `yaml:"type"`
+
+	// Doc is the documentation of the field.
+	Doc string `yaml:"doc"`
+}
+
+// supportedTypes maps the supported types of fields to the import paths they require.
+var supportedTypes = map[string]string{
+	"bool":          "",
+	"string":        "",
+	"int":           "",
+	"int8":          "",
+	"int16":         "",
+	"int32":         "",
+	"int64":         "",
+	"uint":          "",
+	"uint8":         "",
+	"uint16":        "",
+	"uint32":        "",
+	"uint64":        "",
+	"float32":       "",
+	"float64":       "",
+	"[]byte":        "",
+	"[]string":      "",
+	"error":         "",
+	"any":           "",
+	"time.Time":     "time",
+	"time.Duration": "time",
+}
+
+// reservedNames are the identifiers referred by the generated code (the
+// packages it imports and the types of the fields), thus they could not
+// be used as names of arguments.
+var reservedNames = func() map[string]struct{} {
+	result := map[string]struct{}{
+		"ctx":         {},
+		"context":     {},
+		"field":       {},
+		"logger":      {},
+		"eventschema": {},
+	}
+	for typeName := range supportedTypes {
+		// for example "[]byte" refers "byte" and "time.Time" refers "time"
+		name, _, _ := strings.Cut(strings.TrimPrefix(typeName, "[]"), ".")
+		result[name] = struct{}{}
+	}
+	return result
+}()
+
+// LoadSchema reads and validates a schema from a YAML or JSON file.
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the schema: %w", err)
+	}
+	schema, err := ParseSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema '%s': %w", path, err)
+	}
+	return schema, nil
+}
+
+// ParseSchema parses and validates a schema in YAML or JSON format
+// (the latter is a subset of the former). Omitted optional values
+// are filled with their defaults.
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	if err := schema.normalize(); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (schema *Schema) normalize() error {
+	if len(schema.Events) == 0 {
+		return fmt.Errorf("no events defined")
+	}
+	if schema.Package != "" && !token.IsIdentifier(schema.Package) {
+		return fmt.Errorf("invalid package name '%s'", schema.Package)
+	}
+	eventNames := map[string]struct{}{}
+	for idx := range schema.Events {
+		ev := &schema.Events[idx]
+		if !token.IsExported(ev.Name) || !token.IsIdentifier(ev.Name) {
+			return fmt.Errorf("event #%d: name '%s' is not an exported Go identifier", idx, ev.Name)
+		}
+		if _, ok := eventNames[ev.Name]; ok {
+			return fmt.Errorf("event '%s' is defined twice", ev.Name)
+		}
+		eventNames[ev.Name] = struct{}{}
+		if err := ev.normalize(); err != nil {
+			return fmt.Errorf("event '%s': %w", ev.Name, err)
+		}
+	}
+	return nil
+}
+
+func (ev *Event) normalize() error {
+	if ev.Level == "" {
+		ev.Level = types.LevelInfo.String()
+	}
+	level, err := types.ParseLogLevel(ev.Level)
+	if err != nil {
+		return err
+	}
+	if _, ok := levelIdentifiers[level]; !ok {
+		return fmt.Errorf("level '%s' is not supported", ev.Level)
+	}
+	ev.Level = level.String()
+	if ev.Message == "" {
+		ev.Message = strings.ReplaceAll(snakeCase(ev.Name), "_", " ")
+	}
+
+	names := map[string]struct{}{}
+	keys := map[string]struct{}{eventschema.EventKey: {}}
+	for idx := range ev.Fields {
+		f := &ev.Fields[idx]
+		if !token.IsIdentifier(f.Name) || token.IsKeyword(f.Name) {
+			return fmt.Errorf("field #%d: name '%s' is not a valid name of an argument", idx, f.Name)
+		}
+		if _, ok := reservedNames[f.Name]; ok {
+			return fmt.Errorf("field #%d: name '%s' is reserved by the generated code", idx, f.Name)
+		}
+		if _, ok := names[f.Name]; ok {
+			return fmt.Errorf("field '%s' is defined twice", f.Name)
+		}
+		names[f.Name] = struct{}{}
+		if f.Key == "" {
+			f.Key = snakeCase(f.Name)
+		}
+		if _, ok := keys[f.Key]; ok {
+			return fmt.Errorf("field '%s': key '%s' is already used", f.Name, f.Key)
+		}
+		keys[f.Key] = struct{}{}
+		if _, ok := supportedTypes[f.Type]; !ok {
+			return fmt.Errorf("field '%s': type '%s' is not supported", f.Name, f.Type)
+		}
+	}
+	return nil
+}
+
+// snakeCase converts an identifier to snake case, for example
+// "userID" to "user_id" and "HTTPMethod" to "http_method".
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var result strings.Builder
+	for idx, r := range runes {
+		if unicode.IsUpper(r) {
+			prev := rune(0)
+			if idx > 0 {
+				prev = runes[idx-1]
+			}
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && idx+1 < len(runes) && unicode.IsLower(runes[idx+1])) {
+				result.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
@@ -0,0 +1,448 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/facebookincubator/go-belt/pkg/valuesparser"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	directive       = "//fieldsgen:generate"
+	generatedHeader = "// Code generated by fieldsgen; DO NOT EDIT."
+
+	fieldPkgPath        = "github.com/facebookincubator/go-belt/pkg/field"
+	valuesParserPkgPath = "github.com/facebookincubator/go-belt/pkg/valuesparser"
+)
+
+// Result is the outcome of Generate.
+type Result struct {
+	// TypeNames is the list of structures the methods were generated for.
+	TypeNames []string
+
+	// Source is the generated (and formatted) Go source code.
+	Source []byte
+}
+
+// Generate generates methods ForEachField and Len for the structures `typeNames`
+// of the package in directory `dir`. If `typeNames` is empty, then the structures
+// annotated with comment "//fieldsgen:generate" are used.
+func Generate(dir string, typeNames []string) (*Result, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:      packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir:       dir,
+		ParseFile: parseFileIgnoringGenerated,
+	}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the package in '%s': %w", dir, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected exactly one package in '%s', but found %d", dir, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) != 0 {
+		return nil, fmt.Errorf("unable to load the package in '%s': %v", dir, pkg.Errors[0])
+	}
+
+	if len(typeNames) == 0 {
+		typeNames = annotatedTypeNames(pkg.Syntax)
+		if len(typeNames) == 0 {
+			return nil, fmt.Errorf("no structures annotated with '%s' found in '%s'", directive, dir)
+		}
+	}
+
+	g := &generator{
+		pkg:     pkg.Types,
+		imports: map[string]string{fieldPkgPath: "field"},
+	}
+	for _, typeName := range typeNames {
+		if err := g.generateType(typeName); err != nil {
+			return nil, err
+		}
+	}
+
+	source, err := g.source()
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		TypeNames: typeNames,
+		Source:    source,
+	}, nil
+}
+
+// parseFileIgnoringGenerated parses a file, but drops the content of
+// previously generated files: they may be outdated and not compile anymore.
+func parseFileIgnoringGenerated(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	if !bytes.HasPrefix(src, []byte(generatedHeader)) {
+		return parser.ParseFile(fset, filename, src, parser.ParseComments)
+	}
+	return parser.ParseFile(fset, filename, src, parser.PackageClauseOnly)
+}
+
+func annotatedTypeNames(files []*ast.File) []string {
+	var result []string
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				if hasDirective(doc) {
+					result = append(result, typeSpec.Name.Name)
+				}
+			}
+		}
+	}
+	return result
+}
+
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == directive {
+			return true
+		}
+	}
+	return false
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string
+	buf     bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+	g.imports[pkg.Path()] = pkg.Name()
+	return pkg.Name()
+}
+
+func (g *generator) generateType(typeName string) error {
+	obj, ok := g.pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("type '%s' is not found in package '%s'", typeName, g.pkg.Path())
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return fmt.Errorf("'%s' is not a defined type", typeName)
+	}
+	if named.TypeParams().Len() != 0 {
+		return fmt.Errorf("generic type '%s' is not supported", typeName)
+	}
+	structType, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return fmt.Errorf("type '%s' is not a structure", typeName)
+	}
+
+	bodyStart := g.buf.Len()
+	g.generateStruct("v", structType, nil)
+	body := string(g.buf.Bytes()[bodyStart:])
+	g.buf.Truncate(bodyStart)
+
+	g.printf("// ForEachField implements field.AbstractFields.\n")
+	g.printf("func (v %s) ForEachField(callback func(f *field.Field) bool) bool {\n", typeName)
+	if body != "" {
+		g.printf("var f field.Field\n")
+		g.buf.WriteString(body)
+	}
+	g.printf("return true\n}\n\n")
+
+	g.printf("// Len implements field.AbstractFields.\n")
+	g.printf("func (v %s) Len() int {\n", typeName)
+	g.printf("count := 0\n")
+	g.printf("v.ForEachField(func(*field.Field) bool {\ncount++\nreturn true\n})\n")
+	g.printf("return count\n}\n\n")
+	return nil
+}
+
+func (g *generator) generateStruct(expr string, structType *types.Struct, path []string) {
+	for idx := 0; idx < structType.NumFields(); idx++ {
+		structField := structType.Field(idx)
+		if !structField.Exported() {
+			continue
+		}
+		tag := valuesparser.ParseTag(reflect.StructTag(structType.Tag(idx)).Get("log"))
+		if tag.Skip {
+			continue
+		}
+		pathComponent := structField.Name()
+		if tag.Name != "" {
+			pathComponent = tag.Name
+		}
+		fieldPath := append(path[:len(path):len(path)], pathComponent)
+		nestedPath := fieldPath
+		if tag.Inline {
+			nestedPath = path
+		}
+		g.generateValue(expr+"."+structField.Name(), structField.Type(), tag, fieldPath, nestedPath)
+	}
+}
+
+func (g *generator) generateValue(expr string, t types.Type, tag valuesparser.Tag, path, nestedPath []string) {
+	valueExpr := expr
+	valueType := t
+	var condition string
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		condition = expr + " != nil"
+		// a LazyValue is computed only if it is actually used, so it is passed as is
+		if !isLazyValuePointer(t) {
+			valueExpr = "*" + expr
+			valueType = u.Elem()
+		}
+	case *types.Slice, *types.Map:
+		if tag.OmitEmpty {
+			condition = "len(" + expr + ") != 0"
+		} else {
+			condition = expr + " != nil"
+		}
+	case *types.Struct:
+		if tag.Redact || tag.String {
+			condition = g.nonZeroCondition(expr, t)
+		}
+		// otherwise a zero structure has only zero fields, so no need to check it
+	default:
+		condition = g.nonZeroCondition(expr, t)
+	}
+
+	if condition != "" {
+		g.printf("if %s {\n", condition)
+		defer g.printf("}\n")
+	}
+
+	switch {
+	case tag.Redact:
+		g.imports[valuesParserPkgPath] = "valuesparser"
+		g.generateEmit(path, "valuesparser.RedactedValue")
+		return
+	case tag.String:
+		g.generateEmit(path, g.stringExpr(expr, t))
+		return
+	}
+
+	switch u := valueType.Underlying().(type) {
+	case *types.Struct:
+		g.generateNestedStruct(expr, valueType, nestedPath)
+		return
+	case *types.Map:
+		if tag.Inline {
+			g.generateInlineMap(valueExpr, u, nestedPath)
+			return
+		}
+	}
+	g.generateEmit(path, valueExpr)
+}
+
+func (g *generator) generateNestedStruct(expr string, t types.Type, path []string) {
+	if named, ok := t.(*types.Named); ok && isRecursive(named) {
+		// the depth is known only in runtime
+		g.imports["reflect"] = "reflect"
+		g.imports[valuesParserPkgPath] = "valuesparser"
+		quotedPath := make([]string, 0, len(path))
+		for _, pathComponent := range path {
+			quotedPath = append(quotedPath, strconv.Quote(pathComponent))
+		}
+		g.printf("if !valuesparser.ParseStructValue([]string{%s}, reflect.ValueOf(%s), callback) {\nreturn false\n}\n", strings.Join(quotedPath, ", "), expr)
+		return
+	}
+	g.generateStruct(expr, t.Underlying().(*types.Struct), path)
+}
+
+func (g *generator) generateInlineMap(expr string, mapType *types.Map, path []string) {
+	keyPrefix := strings.Join(path, ".")
+	if keyPrefix != "" {
+		keyPrefix += "."
+	}
+	keyExpr := "fmt.Sprint(mapKey)"
+	if basic, ok := mapType.Key().(*types.Basic); ok && basic.Kind() == types.String {
+		keyExpr = "mapKey"
+	} else {
+		g.imports["fmt"] = "fmt"
+	}
+	if keyPrefix != "" {
+		keyExpr = strconv.Quote(keyPrefix) + " + " + keyExpr
+	}
+	g.printf("for mapKey, mapValue := range %s {\n", expr)
+	g.printf("f.Key = %s\n", keyExpr)
+	g.printf("f.Value = mapValue\n")
+	g.printf("if !callback(&f) {\nreturn false\n}\n")
+	g.printf("}\n")
+}
+
+func (g *generator) generateEmit(path []string, valueExpr string) {
+	g.printf("f.Key = %s\n", strconv.Quote(strings.Join(path, ".")))
+	g.printf("f.Value = %s\n", valueExpr)
+	g.printf("if !callback(&f) {\nreturn false\n}\n")
+}
+
+// stringExpr returns a Go expression which converts `expr` to a string the same way
+// as valuesparser does it for the fields with tag option "string".
+func (g *generator) stringExpr(expr string, t types.Type) string {
+	if types.Implements(t, stringerType) {
+		return expr + ".String()"
+	}
+	_, isPointer := t.Underlying().(*types.Pointer)
+	if !isPointer && !types.IsInterface(t) && types.Implements(types.NewPointer(t), stringerType) {
+		// `expr` is always addressable, since it is a field of the receiver
+		return expr + ".String()"
+	}
+	g.imports["fmt"] = "fmt"
+	if isPointer {
+		return "fmt.Sprint(*" + expr + ")"
+	}
+	return "fmt.Sprint(" + expr + ")"
+}
+
+// nonZeroCondition returns a Go expression which is true if `expr`
+// is not a zero value, the same as !reflect.Value.IsZero() does.
+func (g *generator) nonZeroCondition(expr string, t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case info&types.IsBoolean != 0:
+			return expr
+		case info&types.IsString != 0:
+			return expr + ` != ""`
+		case info&types.IsNumeric != 0:
+			return expr + " != 0"
+		case u.Kind() == types.UnsafePointer:
+			return expr + " != nil"
+		}
+	case *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface, *types.Pointer:
+		return expr + " != nil"
+	case *types.Array, *types.Struct:
+		if types.Comparable(t) {
+			return fmt.Sprintf("%s != (%s{})", expr, types.TypeString(t, g.qualifier))
+		}
+	}
+	g.imports["reflect"] = "reflect"
+	return fmt.Sprintf("!reflect.ValueOf(%s).IsZero()", expr)
+}
+
+var stringerType = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "String", types.NewSignatureType(
+		nil, nil, nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.String])),
+		false,
+	)),
+}, nil).Complete()
+
+// isRecursive returns true if the structure could contain itself (through
+// the fields parsed by valuesparser.ParseStructValue).
+func isRecursive(named *types.Named) bool {
+	var walk func(t types.Type, seen map[*types.Named]bool) bool
+	walk = func(t types.Type, seen map[*types.Named]bool) bool {
+		if pointer, ok := t.Underlying().(*types.Pointer); ok {
+			t = pointer.Elem()
+		}
+		if nested, ok := t.(*types.Named); ok {
+			if nested == named {
+				return true
+			}
+			if seen[nested] {
+				return false
+			}
+			seen[nested] = true
+		}
+		structType, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			return false
+		}
+		for idx := 0; idx < structType.NumFields(); idx++ {
+			structField := structType.Field(idx)
+			tag := valuesparser.ParseTag(reflect.StructTag(structType.Tag(idx)).Get("log"))
+			if !structField.Exported() || tag.Skip || tag.Redact || tag.String || isLazyValuePointer(structField.Type()) {
+				continue
+			}
+			if walk(structField.Type(), seen) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(named.Underlying(), map[*types.Named]bool{})
+}
+
+func isLazyValuePointer(t types.Type) bool {
+	pointer, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := pointer.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == fieldPkgPath && obj.Name() == "LazyValue"
+}
+
+func (g *generator) source() ([]byte, error) {
+	var result bytes.Buffer
+	fmt.Fprintf(&result, "%s\n\npackage %s\n\nimport (\n", generatedHeader, g.pkg.Name())
+	var stdImportPaths, importPaths []string
+	for importPath := range g.imports {
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			importPaths = append(importPaths, importPath)
+		} else {
+			stdImportPaths = append(stdImportPaths, importPath)
+		}
+	}
+	sort.Strings(stdImportPaths)
+	sort.Strings(importPaths)
+	for _, importPath := range stdImportPaths {
+		fmt.Fprintf(&result, "%s\n", strconv.Quote(importPath))
+	}
+	if len(stdImportPaths) != 0 {
+		result.WriteString("\n")
+	}
+	for _, importPath := range importPaths {
+		fmt.Fprintf(&result, "%s\n", strconv.Quote(importPath))
+	}
+	result.WriteString(")\n\n")
+	result.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(result.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format the generated code: %w\n%s", err, result.Bytes())
+	}
+	return formatted, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package example contains structures used to test fieldsgen.
+package example
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+)
+
+//go:generate go run github.com/facebookincubator/go-belt/cmd/fieldsgen
+
+// Request is an example of a structure with various kinds of fields.
+//
+//fieldsgen:generate
+type Request struct {
+	ID        uint64 `log:"request_id"`
+	Path      string
+	Internal  string `log:"-"`
+	Verbose   bool
+	Tags      []string
+	Labels    map[string]string
+	Checksum  [4]byte
+	Duration  time.Duration
+	Expensive *field.LazyValue
+	User      User
+	Session   *Session
+	Payload   any
+	Node      *Node
+	Password  string            `log:",redact"`
+	Headers   map[string]string `log:"header,inline,omitempty"`
+	Args      []string          `log:",omitempty"`
+	Metadata  Metadata          `log:",inline"`
+	Addr      *Addr             `log:"addr,string"`
+	Version   Version           `log:",string"`
+	Counter   *int              `log:",string"`
+
+	secret string
+}
+
+// Metadata is an example of a structure flattened into the parent structure.
+type Metadata struct {
+	Region string `log:"region"`
+	Zone   string `log:"zone"`
+}
+
+// Addr is an example of a fmt.Stringer implemented by a pointer.
+type Addr struct {
+	Host string
+	Port uint16
+}
+
+// String implements fmt.Stringer.
+func (addr *Addr) String() string {
+	return fmt.Sprintf("%s:%d", addr.Host, addr.Port)
+}
+
+// Version is an example of a structure without fmt.Stringer
+// which is logged as a string.
+type Version struct {
+	Major, Minor int
+}
+
+// User is an example of a nested structure.
+type User struct {
+	ID   int `log:"user_id"`
+	Name string
+}
+
+// Session is an example of a nested structure referenced by a pointer.
+type Session struct {
+	Token *string
+	User
+}
+
+// Node is an example of a recursive structure.
+//
+//fieldsgen:generate
+type Node struct {
+	Name string
+	Next *Node
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package example
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/valuesparser"
+	"github.com/stretchr/testify/require"
+)
+
+func gather(forEachField func(callback func(f *field.Field) bool) bool) field.Fields {
+	var result field.Fields
+	forEachField(func(f *field.Field) bool {
+		result = append(result, *f)
+		return true
+	})
+	return result
+}
+
+func TestGeneratedMatchesReflection(t *testing.T) {
+	token := "some-token"
+	counter := 3
+	values := []field.AbstractFields{
+		Request{},
+		Request{
+			ID:        1,
+			Path:      "/some/path",
+			Internal:  "should not be logged",
+			Verbose:   true,
+			Tags:      []string{"a", "b"},
+			Labels:    map[string]string{"k": "v"},
+			Checksum:  [4]byte{1, 2, 3, 4},
+			Duration:  5,
+			Expensive: field.Lazy(func() any { return "expensive" }),
+			User:      User{ID: 2, Name: "user"},
+			Session:   &Session{Token: &token, User: User{Name: "session-user"}},
+			Payload:   struct{ Key string }{Key: "value"},
+			Node:      &Node{Name: "first", Next: &Node{Name: "second", Next: &Node{Name: "third"}}},
+			Password:  "should not be logged",
+			Headers:   map[string]string{"Accept": "*/*"},
+			Args:      []string{},
+			Metadata:  Metadata{Region: "eu", Zone: "a"},
+			Addr:      &Addr{Host: "localhost", Port: 80},
+			Version:   Version{Major: 1, Minor: 2},
+			Counter:   &counter,
+			secret:    "should not be logged",
+		},
+		Node{Name: "first", Next: &Node{Next: &Node{Name: "third"}}},
+		Request{Headers: map[string]string{}, Args: []string{"a"}},
+	}
+
+	for _, value := range values {
+		expected := gather(func(callback func(f *field.Field) bool) bool {
+			return valuesparser.ParseStructValue(nil, reflect.ValueOf(value), callback)
+		})
+		actual := gather(value.ForEachField)
+		require.Equal(t, expected, actual)
+		require.Equal(t, len(expected), value.Len())
+	}
+}
+
+func TestGeneratedStop(t *testing.T) {
+	count := 0
+	r := Request{ID: 1, Path: "/"}.ForEachField(func(f *field.Field) bool {
+		count++
+		return false
+	})
+	require.False(t, r)
+	require.Equal(t, 1, count)
+}
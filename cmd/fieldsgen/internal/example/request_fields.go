@@ -0,0 +1,218 @@
+// Code generated by fieldsgen; DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/valuesparser"
+)
+
+// ForEachField implements field.AbstractFields.
+func (v Request) ForEachField(callback func(f *field.Field) bool) bool {
+	var f field.Field
+	if v.ID != 0 {
+		f.Key = "request_id"
+		f.Value = v.ID
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Path != "" {
+		f.Key = "Path"
+		f.Value = v.Path
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Verbose {
+		f.Key = "Verbose"
+		f.Value = v.Verbose
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Tags != nil {
+		f.Key = "Tags"
+		f.Value = v.Tags
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Labels != nil {
+		f.Key = "Labels"
+		f.Value = v.Labels
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Checksum != ([4]byte{}) {
+		f.Key = "Checksum"
+		f.Value = v.Checksum
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Duration != 0 {
+		f.Key = "Duration"
+		f.Value = v.Duration
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Expensive != nil {
+		f.Key = "Expensive"
+		f.Value = v.Expensive
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.User.ID != 0 {
+		f.Key = "User.user_id"
+		f.Value = v.User.ID
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.User.Name != "" {
+		f.Key = "User.Name"
+		f.Value = v.User.Name
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Session != nil {
+		if v.Session.Token != nil {
+			f.Key = "Session.Token"
+			f.Value = *v.Session.Token
+			if !callback(&f) {
+				return false
+			}
+		}
+		if v.Session.User.ID != 0 {
+			f.Key = "Session.User.user_id"
+			f.Value = v.Session.User.ID
+			if !callback(&f) {
+				return false
+			}
+		}
+		if v.Session.User.Name != "" {
+			f.Key = "Session.User.Name"
+			f.Value = v.Session.User.Name
+			if !callback(&f) {
+				return false
+			}
+		}
+	}
+	if v.Payload != nil {
+		f.Key = "Payload"
+		f.Value = v.Payload
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Node != nil {
+		if !valuesparser.ParseStructValue([]string{"Node"}, reflect.ValueOf(v.Node), callback) {
+			return false
+		}
+	}
+	if v.Password != "" {
+		f.Key = "Password"
+		f.Value = valuesparser.RedactedValue
+		if !callback(&f) {
+			return false
+		}
+	}
+	if len(v.Headers) != 0 {
+		for mapKey, mapValue := range v.Headers {
+			f.Key = mapKey
+			f.Value = mapValue
+			if !callback(&f) {
+				return false
+			}
+		}
+	}
+	if len(v.Args) != 0 {
+		f.Key = "Args"
+		f.Value = v.Args
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Metadata.Region != "" {
+		f.Key = "region"
+		f.Value = v.Metadata.Region
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Metadata.Zone != "" {
+		f.Key = "zone"
+		f.Value = v.Metadata.Zone
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Addr != nil {
+		f.Key = "addr"
+		f.Value = v.Addr.String()
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Version != (Version{}) {
+		f.Key = "Version"
+		f.Value = fmt.Sprint(v.Version)
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Counter != nil {
+		f.Key = "Counter"
+		f.Value = fmt.Sprint(*v.Counter)
+		if !callback(&f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len implements field.AbstractFields.
+func (v Request) Len() int {
+	count := 0
+	v.ForEachField(func(*field.Field) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// ForEachField implements field.AbstractFields.
+func (v Node) ForEachField(callback func(f *field.Field) bool) bool {
+	var f field.Field
+	if v.Name != "" {
+		f.Key = "Name"
+		f.Value = v.Name
+		if !callback(&f) {
+			return false
+		}
+	}
+	if v.Next != nil {
+		if !valuesparser.ParseStructValue([]string{"Next"}, reflect.ValueOf(v.Next), callback) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len implements field.AbstractFields.
+func (v Node) Len() int {
+	count := 0
+	v.ForEachField(func(*field.Field) bool {
+		count++
+		return true
+	})
+	return count
+}
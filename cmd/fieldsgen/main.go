@@ -0,0 +1,83 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Command fieldsgen generates methods ForEachField and Len for structures,
+// so that they implement field.AbstractFields without any reflection.
+//
+// The generated methods follow the same rules as valuesparser.ParseStructValue
+// (which is used to parse structures passed to a Logger): unexported
+// and zero-valued fields are skipped, tag `log:"name"` overrides the field name,
+// tag `log:"-"` excludes the field, the fields of nested structures
+// are named by their path (for example "Request.UserID") and tag options
+// "omitempty", "inline", "redact" and "string" are supported (see valuesparser.Tag).
+//
+// Usage:
+//
+//	//go:generate go run github.com/facebookincubator/go-belt/cmd/fieldsgen
+//
+// generates the methods for all the structures of the package annotated
+// with comment "//fieldsgen:generate", or:
+//
+//	//go:generate go run github.com/facebookincubator/go-belt/cmd/fieldsgen -type=Request,Response
+//
+// generates the methods for the specified structures.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [directory]\n", filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
+}
+
+func main() {
+	typeNamesFlag := flag.String("type", "", "comma-separated list of type names; if empty then all the structures annotated with comment \""+directive+"\" are used")
+	outputFlag := flag.String("output", "", "output file name; default: <directory>/<first type name>_fields.go")
+	flag.Usage = usage
+	flag.Parse()
+
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	var typeNames []string
+	if *typeNamesFlag != "" {
+		typeNames = strings.Split(*typeNamesFlag, ",")
+	}
+
+	result, err := Generate(dir, typeNames)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fieldsgen: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputPath := *outputFlag
+	if outputPath == "" {
+		outputPath = filepath.Join(dir, strings.ToLower(result.TypeNames[0])+"_fields.go")
+	}
+	if err := os.WriteFile(outputPath, result.Source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "fieldsgen: unable to write '%s': %v\n", outputPath, err)
+		os.Exit(1)
+	}
+}
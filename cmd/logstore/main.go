@@ -0,0 +1,143 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Command logstore queries a log store written by package
+// tool/logger/implementation/logstore.
+//
+// The matching entries are printed as JSON objects (one per line), so
+// they could be pretty-printed by command beltlog:
+//
+//	logstore -dir /var/lib/myapp/logs -trace 3f2a9c0e | beltlog
+//	logstore -dir /var/lib/myapp/logs -level error -since 1h -where user_id=42
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/logstore"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -dir <directory> [flags]\n", filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
+}
+
+func main() {
+	var whereFlag stringsFlag
+	dirFlag := flag.String("dir", "", "the directory of the log store")
+	levelFlag := flag.String("level", "", "fetch only entries of this level or more severe ones")
+	traceIDFlag := flag.String("trace", "", "fetch only entries with this trace ID")
+	sinceFlag := flag.String("since", "", "fetch only entries since this time: RFC3339 time or a duration ago (for example 15m)")
+	untilFlag := flag.String("until", "", "fetch only entries before this time: RFC3339 time or a duration ago (for example 5m)")
+	limitFlag := flag.Int("limit", 0, "the maximal amount of entries to fetch (0 means no limit)")
+	flag.Var(&whereFlag, "where", "fetch only entries with the field: key=value; may be repeated")
+	flag.Usage = usage
+	flag.Parse()
+	if *dirFlag == "" || flag.NArg() != 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	q := logstore.Query{
+		TraceID: belt.TraceID(*traceIDFlag),
+		Limit:   *limitFlag,
+	}
+	var err error
+	if *levelFlag != "" {
+		if q.Level, err = types.ParseLogLevel(*levelFlag); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	now := time.Now()
+	if *sinceFlag != "" {
+		if q.Since, err = parseTime(*sinceFlag, now); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	if *untilFlag != "" {
+		if q.Until, err = parseTime(*untilFlag, now); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	var matchers []func(*types.Entry) bool
+	for _, expr := range whereFlag {
+		key, value, ok := strings.Cut(expr, "=")
+		if !ok || key == "" {
+			fatalf("invalid field expression '%s', expected key=value", expr)
+		}
+		matchers = append(matchers, logstore.FieldEquals(key, value))
+	}
+	if len(matchers) > 0 {
+		q.Match = func(entry *types.Entry) bool {
+			for _, match := range matchers {
+				if !match(entry) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	store, err := logstore.Open(*dirFlag, logstore.OptionReadOnly(true))
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	err = store.Query(q, func(entry *types.Entry) bool {
+		out.Write(logstore.MarshalEntry(entry))
+		out.WriteByte('\n')
+		return true
+	})
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+}
+
+// parseTime parses either an RFC3339 time or a duration ago.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return ts, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is neither an RFC3339 time nor a duration", s)
+	}
+	return now.Add(-d), nil
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "logstore: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/DataDog/gostackparse v0.7.0
 	github.com/getsentry/sentry-go v0.31.1
+	github.com/go-logr/logr v1.4.2
 	github.com/go-ng/slices v0.0.0-20230703171042-6195d35636a2
 	github.com/go-ng/sort v0.0.0-20220617173827-2cc7cd04f7c7
 	github.com/go-ng/xatomic v0.0.0-20230519181013-85c0ec87e55f
@@ -15,12 +16,15 @@ require (
 	github.com/prometheus/client_golang v1.20.5
 	github.com/prometheus/client_model v0.6.1
 	github.com/prometheus/common v0.62.0
+	github.com/rs/zerolog v1.33.0
 	github.com/sirupsen/logrus v1.9.3
 	github.com/stretchr/testify v1.10.0
 	github.com/xaionaro-go/metrics v0.0.0-20210425194006-68050b337673
 	github.com/xaionaro-go/unsafetools v0.0.0-20241024014258-a46e1ce3763e
 	go.uber.org/atomic v1.11.0
 	go.uber.org/zap v1.27.0
+	google.golang.org/grpc v1.63.2
+	k8s.io/klog/v2 v2.130.1
 )
 
 require (
@@ -28,6 +32,8 @@ require (
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/prometheus/procfs v0.15.1 // indirect
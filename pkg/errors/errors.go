@@ -0,0 +1,249 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package errors provides errors which remember where and in which
+// context they were created: the stack trace, the TraceIDs and the
+// fields of the Belt.
+//
+// The errors implement field.AbstractFields, so the fields (prefixed by
+// FieldNamePrefix) and the TraceIDs of the context where the error was
+// created are logged together with the error.
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+)
+
+var (
+	// FieldNamePrefix is the prefix added to the keys of the fields of the context
+	// where the error was created, so that they do not clash with the fields
+	// of the Logger which logs the error.
+	FieldNamePrefix = "error."
+
+	// FieldNameTraceIDs is the field name used to provide the TraceIDs
+	// of the context where the error was created.
+	FieldNameTraceIDs = "error.trace_id"
+)
+
+// Error is an error with the stack trace, the TraceIDs and the fields
+// of the context where it was created.
+type Error struct {
+	// Message is the message of this error (without the message of
+	// the wrapped error).
+	Message string
+
+	// Err is the wrapped error (if any).
+	Err error
+
+	// Stack is the stack trace of the place where the error was created.
+	Stack runtime.PCs
+
+	// TraceIDs are the TraceIDs of the context where the error was created.
+	TraceIDs belt.TraceIDs
+
+	// Fields are the fields of the context where the error was created.
+	//
+	// They are provided through ForEachField with keys prefixed by FieldNamePrefix.
+	Fields field.AbstractFields
+}
+
+var (
+	_ error                = (*Error)(nil)
+	_ field.AbstractFields = (*Error)(nil)
+	_ fmt.Formatter        = (*Error)(nil)
+)
+
+// newError is called by the constructors, which are marked as "noinline": if
+// a constructor was inlined, then the frame of its caller would be reported as
+// the constructor, and would be skipped by runtime.DefaultCallerPCFilter.
+func newError(ctx context.Context, err error, message string) *Error {
+	result := &Error{
+		Message: message,
+		Err:     err,
+		Stack:   runtime.CallerStackTrace(nil),
+	}
+	if ctx != nil {
+		observer := belt.CtxBelt(ctx)
+		result.TraceIDs = observer.TraceIDs()
+		result.Fields = observer.Fields()
+	}
+	return result
+}
+
+// New returns a new error with the given message and the stack trace,
+// the TraceIDs and the fields of the given context.
+//
+//go:noinline
+func New(ctx context.Context, message string) error {
+	return newError(ctx, nil, message)
+}
+
+// Errorf is the same as New, but with a formatted message.
+//
+// Same as fmt.Errorf it supports verb "%w" to wrap errors.
+//
+//go:noinline
+func Errorf(ctx context.Context, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	var cause error
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		cause = err.Unwrap()
+	case interface{ Unwrap() []error }:
+		cause = errors.Join(err.Unwrap()...)
+	}
+	return newError(ctx, cause, err.Error())
+}
+
+// Wrap returns a new error wrapping `err` with the given message and
+// the stack trace, the TraceIDs and the fields of the given context.
+//
+// Returns nil if `err` is nil.
+//
+//go:noinline
+func Wrap(ctx context.Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return newError(ctx, err, message+": "+err.Error())
+}
+
+// Wrapf is the same as Wrap, but with a formatted message.
+//
+//go:noinline
+func Wrapf(ctx context.Context, err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return Wrap(ctx, err, fmt.Sprintf(format, args...))
+}
+
+// WithStack returns a new error wrapping `err` (without changing the message)
+// adding the stack trace, the TraceIDs and the fields of the given context.
+//
+// Returns nil if `err` is nil.
+//
+//go:noinline
+func WithStack(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	return newError(ctx, err, err.Error())
+}
+
+// Error implements interface error.
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// Unwrap returns the wrapped error (see errors.Unwrap).
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// StackTrace returns the stack trace of the place where the error was created.
+func (err *Error) StackTrace() runtime.StackTrace {
+	return err.Stack
+}
+
+// ForEachField implements field.AbstractFields.
+func (err *Error) ForEachField(callback func(f *field.Field) bool) bool {
+	if err.Fields != nil {
+		if !err.Fields.ForEachField(func(f *field.Field) bool {
+			prefixed := *f
+			prefixed.Key = FieldNamePrefix + f.Key
+			return callback(&prefixed)
+		}) {
+			return false
+		}
+	}
+	if len(err.TraceIDs) != 0 {
+		f := field.Field{Key: FieldNameTraceIDs, Value: err.TraceIDs}
+		if !callback(&f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len implements field.AbstractFields.
+func (err *Error) Len() int {
+	length := 0
+	if err.Fields != nil {
+		length += err.Fields.Len()
+	}
+	if len(err.TraceIDs) != 0 {
+		length++
+	}
+	return length
+}
+
+// Format implements fmt.Formatter.
+//
+// Verb "%+v" prints the message and the stack trace.
+func (err *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, err.Message)
+		if s.Flag('+') {
+			io.WriteString(s, "\n")
+			io.WriteString(s, err.Stack.String())
+		}
+	case 's':
+		io.WriteString(s, err.Message)
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Message)
+	}
+}
+
+// StackTrace returns the stack trace of the deepest error in the chain of wrapped
+// errors (see errors.Unwrap), which provides one (through method `StackTrace() runtime.StackTrace`).
+//
+// Returns nil if there are no such errors.
+func StackTrace(err error) runtime.StackTrace {
+	var result runtime.StackTrace
+	for ; err != nil; err = errors.Unwrap(err) {
+		if stackTracer, ok := err.(interface{ StackTrace() runtime.StackTrace }); ok {
+			if stackTrace := stackTracer.StackTrace(); stackTrace != nil && stackTrace.Len() != 0 {
+				result = stackTrace
+			}
+		}
+	}
+	return result
+}
+
+// Is is just a proxy to the standard errors.Is.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As is just a proxy to the standard errors.As.
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap is just a proxy to the standard errors.Unwrap.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
+
+// Join is just a proxy to the standard errors.Join.
+func Join(errs ...error) error {
+	return errors.Join(errs...)
+}
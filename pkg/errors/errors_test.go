@@ -0,0 +1,93 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	stdruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	ctx := belt.WithField(context.Background(), "user_id", 1)
+	ctx = belt.WithTraceID(ctx, "some-trace-id")
+
+	err := Wrap(ctx, io.EOF, "unable to read")
+	require.Equal(t, "unable to read: EOF", err.Error())
+	require.True(t, Is(err, io.EOF))
+
+	var beltErr *Error
+	require.True(t, As(err, &beltErr))
+	require.NotZero(t, beltErr.Stack.Len())
+
+	var fields field.Fields
+	beltErr.ForEachField(func(f *field.Field) bool {
+		fields = append(fields, *f)
+		return true
+	})
+	require.Equal(t, field.Fields{
+		{Key: "error.user_id", Value: 1},
+		{Key: FieldNameTraceIDs, Value: belt.TraceIDs{"some-trace-id"}},
+	}, fields)
+	require.Equal(t, len(fields), beltErr.Len())
+
+	require.True(t, strings.HasPrefix(fmt.Sprintf("%+v", err), "unable to read: EOF\n1. "))
+	require.Nil(t, Wrap(ctx, nil, "unable to read"))
+}
+
+func TestErrorCaller(t *testing.T) {
+	// the default filter skips all go-belt functions, including this test
+	oldFilter := runtime.DefaultCallerPCFilter
+	defer func() { runtime.DefaultCallerPCFilter = oldFilter }()
+	runtime.DefaultCallerPCFilter = func(pc uintptr) bool {
+		funcName := stdruntime.FuncForPC(pc).Name()
+		return !strings.HasPrefix(funcName, "github.com/facebookincubator/go-belt/pkg/") ||
+			strings.Contains(funcName, ".Test")
+	}
+
+	for name, err := range map[string]error{
+		"New":       New(context.Background(), "some error"),
+		"Errorf":    Errorf(context.Background(), "some error: %w", io.EOF),
+		"Wrap":      Wrap(context.Background(), io.EOF, "some error"),
+		"Wrapf":     Wrapf(context.Background(), io.EOF, "some error %d", 1),
+		"WithStack": WithStack(context.Background(), io.EOF),
+	} {
+		frame, _ := err.(*Error).Stack.Frames().Next()
+		require.Equal(t, "github.com/facebookincubator/go-belt/pkg/errors.TestErrorCaller", frame.Function, name)
+	}
+}
+
+func TestErrorfMultipleWrapped(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	err := Errorf(context.Background(), "%w and %w", errA, errB)
+	require.Equal(t, "a and b", err.Error())
+	require.True(t, Is(err, errA))
+	require.True(t, Is(err, errB))
+}
+
+func TestStackTrace(t *testing.T) {
+	require.Nil(t, StackTrace(io.EOF))
+
+	inner := New(context.Background(), "inner")
+	outer := WithStack(context.Background(), fmt.Errorf("outer: %w", inner))
+	require.Equal(t, inner.(*Error).Stack, StackTrace(outer))
+}
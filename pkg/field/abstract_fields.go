@@ -58,6 +58,16 @@ func (s Slice[T]) ForEachField(callback func(f *Field) bool) bool {
 	return true
 }
 
+// HasErrors implements ErrorsDetector.
+func (s Slice[T]) HasErrors() bool {
+	for _, items := range s {
+		if hasErrors(items) {
+			return true
+		}
+	}
+	return false
+}
+
 // Slicer implements AbstractFields providing a subset of fields (reported through
 // method ForEachField of the initial collection).
 type Slicer[T AbstractFields] struct {
@@ -95,6 +105,14 @@ func (s *Slicer[T]) ForEachField(callback func(f *Field) bool) bool {
 	})
 }
 
+// HasErrors implements ErrorsDetector.
+//
+// It reports the errors of the initial collection, so it may return
+// true even if the subset has no fields containing an error.
+func (s *Slicer[T]) HasErrors() bool {
+	return hasErrors(s.All)
+}
+
 // NewSlicer provides a subset of fields (reported through method ForEachField of the initial collection).
 func NewSlicer[T AbstractFields](all T, startIdx, endIdx uint) *Slicer[T] {
 	return &Slicer[T]{
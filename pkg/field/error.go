@@ -0,0 +1,200 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package field
+
+import (
+	"fmt"
+)
+
+const (
+	// ErrorKey is the default key of the field containing an error.
+	ErrorKey = "error"
+
+	// ErrorTypeKeySuffix is the suffix added to the key of an error
+	// field to get the key of the field with the Go type of the error.
+	ErrorTypeKeySuffix = ".type"
+
+	// ErrorChainKeySuffix is the suffix added to the key of an error
+	// field to get the key of the field with the Go types of all
+	// the errors in the chain of wrapped errors.
+	ErrorChainKeySuffix = ".chain"
+
+	// errorChainMaxDepth limits the walking through the chain of wrapped
+	// errors, to protect against cyclic or pathologically deep chains.
+	errorChainMaxDepth = 64
+)
+
+// ForEachErrorField calls the callback for each field of the stable
+// set of fields describing the error, until first false is returned:
+//
+//   - `key` contains the error itself (the message of the error);
+//   - `key` + ErrorTypeKeySuffix contains the Go type of the error;
+//   - `key` + ErrorChainKeySuffix contains the Go types of all the errors
+//     of the chain unwrapped through `Unwrap() error` and `Unwrap() []error`
+//     (see errors.Unwrap and errors.Join), in depth-first order;
+//
+// and then for each field provided by the errors of the chain which
+// implement ForEachFieldser (in the same order).
+//
+// It returns false if callback returned false.
+func ForEachErrorField(key Key, err error, callback func(f *Field) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	var chain []string
+	walkErrorChain(err, 0, func(err error) {
+		chain = append(chain, fmt.Sprintf("%T", err))
+	})
+
+	f := Field{Key: key, Value: err}
+	if !callback(&f) {
+		return false
+	}
+	f = Field{Key: key + ErrorTypeKeySuffix, Value: chain[0]}
+	if !callback(&f) {
+		return false
+	}
+	f = Field{Key: key + ErrorChainKeySuffix, Value: chain}
+	if !callback(&f) {
+		return false
+	}
+
+	result := true
+	walkErrorChain(err, 0, func(err error) {
+		if !result {
+			return
+		}
+		if fieldser, ok := err.(ForEachFieldser); ok {
+			result = fieldser.ForEachField(callback)
+		}
+	})
+	return result
+}
+
+func walkErrorChain(err error, depth int, callback func(err error)) {
+	if err == nil || depth >= errorChainMaxDepth {
+		return
+	}
+	callback(err)
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		walkErrorChain(err.Unwrap(), depth+1, callback)
+	case interface{ Unwrap() []error }:
+		for _, child := range err.Unwrap() {
+			walkErrorChain(child, depth+1, callback)
+		}
+	}
+}
+
+// ErrorsExpander replaces each field which contains an error
+// with the fields provided by ForEachErrorField (with the same key).
+//
+// Use ExpandErrors to construct it.
+type ErrorsExpander struct {
+	Fields AbstractFields
+
+	// length is the amount of fields after the expansion;
+	// zero means it is not calculated.
+	length int
+}
+
+var _ AbstractFields = (*ErrorsExpander)(nil)
+
+// ErrorsDetector is an optional interface of AbstractFields, which
+// allows ExpandErrors to check if there are fields containing an error
+// without iterating the fields (for example if the fields are converted
+// lazily on each iteration).
+type ErrorsDetector interface {
+	// HasErrors returns false only if there are no fields containing an error.
+	HasErrors() bool
+}
+
+// ExpandErrors replaces each field which contains an error
+// with the fields provided by ForEachErrorField (with the same key).
+//
+// If there are no fields containing an error, then the fields
+// are returned as is (to avoid any overhead on iterating them later).
+// All the collections of this package implement ErrorsDetector, so
+// the fields are iterated in advance only if they contain an error
+// (or if they are of a type which does not implement ErrorsDetector).
+func ExpandErrors(fields AbstractFields) AbstractFields {
+	if fields == nil {
+		return nil
+	}
+	if detector, ok := fields.(ErrorsDetector); ok && !detector.HasErrors() {
+		return fields
+	}
+	length, hasErrors := expandedLen(fields)
+	if !hasErrors {
+		return fields
+	}
+	return ErrorsExpander{
+		Fields: fields,
+		length: length,
+	}
+}
+
+// hasErrors returns false only if there are no fields containing an error.
+func hasErrors(fields AbstractFields) bool {
+	if detector, ok := fields.(ErrorsDetector); ok {
+		return detector.HasErrors()
+	}
+	_, hasErrors := expandedLen(fields)
+	return hasErrors
+}
+
+// expandedLen returns the amount of fields after expanding the errors
+// and true if there was at least one field with an error.
+func expandedLen(fields AbstractFields) (int, bool) {
+	count := 0
+	hasErrors := false
+	fields.ForEachField(func(f *Field) bool {
+		err, ok := f.Value.(error)
+		if !ok {
+			count++
+			return true
+		}
+		hasErrors = true
+		count += 3 // the error, its type and its chain
+		walkErrorChain(err, 0, func(err error) {
+			if fieldser, ok := err.(ForEachFieldser); ok {
+				fieldser.ForEachField(func(*Field) bool {
+					count++
+					return true
+				})
+			}
+		})
+		return true
+	})
+	return count, hasErrors
+}
+
+// ForEachField implements AbstractFields.
+func (e ErrorsExpander) ForEachField(callback func(f *Field) bool) bool {
+	return e.Fields.ForEachField(func(f *Field) bool {
+		if err, ok := f.Value.(error); ok {
+			return ForEachErrorField(f.Key, err, callback)
+		}
+		return callback(f)
+	})
+}
+
+// Len implements AbstractFields.
+func (e ErrorsExpander) Len() int {
+	if e.length != 0 {
+		return e.length
+	}
+	length, _ := expandedLen(e.Fields)
+	return length
+}
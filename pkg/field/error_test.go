@@ -0,0 +1,141 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package field
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testError struct {
+	Fields
+}
+
+func (testError) Error() string {
+	return "test error"
+}
+
+func TestExpandErrors(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", testError{Fields: Fields{{Key: "user_id", Value: 1}}})
+	fields := ExpandErrors(Fields{
+		{Key: "some_key", Value: "some_value"},
+		{Key: "cause", Value: err},
+	})
+
+	var result Fields
+	fields.ForEachField(func(f *Field) bool {
+		result = append(result, *f)
+		return true
+	})
+	require.Equal(t, Fields{
+		{Key: "some_key", Value: "some_value"},
+		{Key: "cause", Value: err},
+		{Key: "cause.type", Value: "*fmt.wrapError"},
+		{Key: "cause.chain", Value: []string{"*fmt.wrapError", "field.testError"}},
+		{Key: "user_id", Value: 1},
+	}, result)
+	require.Equal(t, len(result), fields.Len())
+}
+
+func TestExpandErrorsNoErrors(t *testing.T) {
+	fields := Fields{
+		{Key: "some_key", Value: "some_value"},
+	}
+	require.Equal(t, fields, ExpandErrors(fields))
+	require.Nil(t, ExpandErrors(nil))
+}
+
+type detectingFields struct {
+	Fields
+	hasErrors bool
+}
+
+func (fields detectingFields) HasErrors() bool {
+	return fields.hasErrors
+}
+
+func TestExpandErrorsDetector(t *testing.T) {
+	err := testError{}
+	fields := detectingFields{Fields: Fields{{Key: "cause", Value: err}}}
+	require.Equal(t, fields, ExpandErrors(fields))
+
+	fields.hasErrors = true
+	expanded := ExpandErrors(fields)
+	require.Equal(t, 3, expanded.Len())
+}
+
+func TestHasErrors(t *testing.T) {
+	err := testError{}
+	for name, fields := range map[string]func(value Value) AbstractFields{
+		"Field": func(value Value) AbstractFields {
+			return &Field{Key: "cause", Value: value}
+		},
+		"Fields": func(value Value) AbstractFields {
+			return Fields{{Key: "some_key", Value: 1}, {Key: "cause", Value: value}}
+		},
+		"FieldsChain": func(value Value) AbstractFields {
+			return NewChainFromOne("cause", value).WithFields(Fields{{Key: "some_key", Value: 1}}).WithField("other_key", 2)
+		},
+		"Map": func(value Value) AbstractFields {
+			return Map[Value]{"some_key": 1, "cause": value}
+		},
+		"MapGeneric": func(value Value) AbstractFields {
+			return MapGeneric[int, Value]{1: 1, 2: value}
+		},
+		"Slice": func(value Value) AbstractFields {
+			return Slice[AbstractFields]{Fields{{Key: "some_key", Value: 1}}, &Field{Key: "cause", Value: value}}
+		},
+		"Slicer": func(value Value) AbstractFields {
+			return NewSlicer[AbstractFields](Fields{{Key: "cause", Value: value}}, 0, 1)
+		},
+		"Prefixer": func(value Value) AbstractFields {
+			return Prefix("prefix.", Fields{{Key: "cause", Value: value}})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Implements(t, (*ErrorsDetector)(nil), fields(nil))
+			require.False(t, fields("not an error").(ErrorsDetector).HasErrors())
+			require.True(t, fields(err).(ErrorsDetector).HasErrors())
+			require.Equal(t, fields(err).Len()+2, ExpandErrors(fields(err)).Len())
+		})
+	}
+}
+
+var expandErrorsResult AbstractFields
+
+func BenchmarkExpandErrors(b *testing.B) {
+	for _, fieldCount := range []int{0, 1, 10, 100} {
+		b.Run(fmt.Sprintf("fieldCount%d", fieldCount), func(b *testing.B) {
+			fields := dummyFields(uint(fieldCount))
+			m := Map[Value]{}
+			for _, f := range fields {
+				m[f.Key] = f.Value
+			}
+			for name, fields := range map[string]AbstractFields{
+				"Fields":      &fields,
+				"FieldsChain": (*FieldsChain)(nil).WithFields(fields),
+				"Map":         m,
+			} {
+				b.Run(name, func(b *testing.B) {
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						expandErrorsResult = ExpandErrors(fields)
+					}
+				})
+			}
+		})
+	}
+}
@@ -44,6 +44,12 @@ func (f *Field) Len() int {
 	return 1
 }
 
+// HasErrors implements ErrorsDetector.
+func (f *Field) HasErrors() bool {
+	_, ok := f.Value.(error)
+	return ok
+}
+
 // Fields is a slice of Field-s
 type Fields []Field
 
@@ -80,3 +86,13 @@ func (s Fields) ForEachField(callback func(f *Field) bool) bool {
 func (s Fields) Len() int {
 	return len(s)
 }
+
+// HasErrors implements ErrorsDetector.
+func (s Fields) HasErrors() bool {
+	for idx := range s {
+		if _, ok := s[idx].Value.(error); ok {
+			return true
+		}
+	}
+	return false
+}
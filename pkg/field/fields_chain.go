@@ -85,6 +85,22 @@ func (fields *FieldsChain) Len() int {
 	return length
 }
 
+// HasErrors implements ErrorsDetector.
+func (fields *FieldsChain) HasErrors() bool {
+	for ; fields != nil; fields = fields.parent {
+		if fields.multipleFields != nil {
+			if hasErrors(fields.multipleFields) {
+				return true
+			}
+			continue
+		}
+		if _, ok := fields.oneField.Value.(error); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // WithField adds the field to the chain (from the forward -- FIFO) and
 // returns the pointer to the new beginning.
 func (fields *FieldsChain) WithField(key Key, value Value, props ...Property) *FieldsChain {
@@ -0,0 +1,79 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// LazyValue is a Value which is computed only if (and when) it is actually
+// required by a Tool. For example a Logger computes it only if the entry
+// is actually emitted (thus it is not computed if the entry is filtered
+// out by the logging level).
+//
+// The function is called at most once, and the result is reused.
+type LazyValue struct {
+	once  sync.Once
+	fn    func() Value
+	value Value
+}
+
+// Lazy returns a Value which will be computed using the given function
+// only if (and when) it is actually required.
+//
+// Example:
+//
+//	logger.FromCtx(ctx).WithField("dump", field.Lazy(func() any {
+//		return expensiveDump()
+//	})).Debug("current state")
+func Lazy(fn func() Value) *LazyValue {
+	return &LazyValue{fn: fn}
+}
+
+// Evaluate returns the value computing it on the first call.
+func (v *LazyValue) Evaluate() Value {
+	v.once.Do(func() {
+		if v.fn == nil {
+			return
+		}
+		v.value = v.fn()
+		v.fn = nil
+	})
+	return v.value
+}
+
+// String implements fmt.Stringer.
+func (v *LazyValue) String() string {
+	return fmt.Sprint(v.Evaluate())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v *LazyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Evaluate())
+}
+
+// ResolveValue returns the actual value if the given value is a lazy one
+// (a *LazyValue or a `func() any`), otherwise the value is returned as is.
+//
+// Note: unlike *LazyValue a `func() any` is called on each ResolveValue call.
+func ResolveValue(value Value) Value {
+	switch v := value.(type) {
+	case *LazyValue:
+		return v.Evaluate()
+	case func() Value:
+		return v()
+	}
+	return value
+}
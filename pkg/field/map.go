@@ -40,6 +40,16 @@ func (m Map[V]) ForEachField(callback func(f *Field) bool) bool {
 	return true
 }
 
+// HasErrors implements ErrorsDetector.
+func (m Map[V]) HasErrors() bool {
+	for _, v := range m {
+		if _, ok := any(v).(error); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Map is an abstract map which implements field.AbstractFields.
 //
 // It differs from Map, because it also accepts non-string keys.
@@ -64,3 +74,13 @@ func (m MapGeneric[K, V]) ForEachField(callback func(f *Field) bool) bool {
 func (m MapGeneric[K, V]) Len() int {
 	return len(m)
 }
+
+// HasErrors implements field.ErrorsDetector.
+func (m MapGeneric[K, V]) HasErrors() bool {
+	for _, v := range m {
+		if _, ok := any(v).(error); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -44,3 +44,8 @@ func (p Prefixer) ForEachField(callback func(f *Field) bool) bool {
 func (p Prefixer) Len() int {
 	return p.Fields.Len()
 }
+
+// HasErrors implements ErrorsDetector.
+func (p Prefixer) HasErrors() bool {
+	return hasErrors(p.Fields)
+}
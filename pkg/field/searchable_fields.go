@@ -164,6 +164,11 @@ func (s *SearchableFields) Len() int {
 	return len(s.fields)
 }
 
+// HasErrors implements ErrorsDetector.
+func (s *SearchableFields) HasErrors() bool {
+	return s.fields.HasErrors()
+}
+
 // Cap returns the capacity of the storage of the Fields.
 func (s *SearchableFields) Cap() int {
 	return cap(s.fields)
@@ -0,0 +1,192 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package messagetemplate implements message templates with named
+// placeholders, like "user {user_id} logged in from {ip}".
+//
+// A placeholder is rendered into the message and is also provided as
+// a structured field, while the template itself is provided as a stable
+// field (see FieldNameMessageTemplate), which could be used to group
+// messages regardless of the values.
+package messagetemplate
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/valuesparser"
+)
+
+var (
+	// FieldNameMessageTemplate is the field name used to provide the template of a message.
+	FieldNameMessageTemplate = "message_template"
+
+	// MaxCacheSize is the maximal amount of parsed templates kept by Parse.
+	//
+	// Templates are supposed to be constants, so the cache is never evicted;
+	// after reaching the limit the templates are just parsed on every call.
+	MaxCacheSize = 4096
+)
+
+var (
+	cache     sync.Map
+	cacheSize atomic.Int64
+)
+
+type segment struct {
+	// Literal is the text to be rendered as is (if Placeholder is negative).
+	Literal string
+
+	// Placeholder is the index of the placeholder in Template.Placeholders,
+	// or -1 if the segment is a literal.
+	Placeholder int
+}
+
+// Template is a parsed message template.
+type Template struct {
+	// Text is the source text of the template.
+	Text string
+
+	// Placeholders is the list of unique placeholder names in order of appearance.
+	Placeholders []string
+
+	segments []segment
+}
+
+// Parse returns the parsed template (caching the result).
+//
+// The syntax:
+//   - "{name}" is a placeholder, where name consists of letters,
+//     digits and characters "_", "." and "-";
+//   - "{{" and "}}" are rendered as "{" and "}";
+//   - anything else is rendered as is.
+func Parse(text string) *Template {
+	if cached, ok := cache.Load(text); ok {
+		return cached.(*Template)
+	}
+	t := parse(text)
+	if cacheSize.Load() < int64(MaxCacheSize) {
+		if _, loaded := cache.LoadOrStore(text, t); !loaded {
+			cacheSize.Add(1)
+		}
+	}
+	return t
+}
+
+func parse(text string) *Template {
+	t := &Template{Text: text}
+	var literal strings.Builder
+	flushLiteral := func() {
+		if literal.Len() == 0 {
+			return
+		}
+		t.segments = append(t.segments, segment{Literal: literal.String(), Placeholder: -1})
+		literal.Reset()
+	}
+
+	for idx := 0; idx < len(text); idx++ {
+		c := text[idx]
+		switch {
+		case c == '{' && idx+1 < len(text) && text[idx+1] == '{',
+			c == '}' && idx+1 < len(text) && text[idx+1] == '}':
+			literal.WriteByte(c)
+			idx++
+			continue
+		case c != '{':
+			literal.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(text[idx+1:], '}')
+		if end < 1 || !isValidName(text[idx+1:idx+1+end]) {
+			literal.WriteByte(c)
+			continue
+		}
+		name := text[idx+1 : idx+1+end]
+		flushLiteral()
+		t.segments = append(t.segments, segment{Placeholder: t.placeholderIndex(name)})
+		idx += end + 1
+	}
+	flushLiteral()
+	return t
+}
+
+func (t *Template) placeholderIndex(name string) int {
+	for idx, placeholder := range t.Placeholders {
+		if placeholder == name {
+			return idx
+		}
+	}
+	t.Placeholders = append(t.Placeholders, name)
+	return len(t.Placeholders) - 1
+}
+
+func isValidName(name string) bool {
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '_', c == '.', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Execute renders the message and collects the structured fields.
+//
+// The arguments are assigned to the placeholders positionally (a placeholder
+// used multiple times consumes a single argument). A placeholder without
+// an argument is rendered as is and is not provided as a field. Excessive arguments
+// are handled the same way as by Logger.Log: structured values (see valuesparser.AnySlice)
+// are provided as fields and the rest is appended to the message.
+//
+// The resulting fields are the placeholders, the template (see FieldNameMessageTemplate)
+// and the fields of the excessive arguments.
+func (t *Template) Execute(args ...any) (string, field.Fields) {
+	fields := make(field.Fields, 0, len(t.Placeholders)+1)
+	for idx, name := range t.Placeholders {
+		if idx >= len(args) {
+			break
+		}
+		fields = append(fields, field.Field{Key: name, Value: args[idx]})
+	}
+	fields = append(fields, field.Field{Key: FieldNameMessageTemplate, Value: t.Text})
+
+	var excessive valuesparser.AnySlice
+	if len(args) > len(t.Placeholders) {
+		// copying, because AnySlice.ForEachField modifies the slice
+		excessive = append(excessive, args[len(t.Placeholders):]...)
+		excessive.ForEachField(func(f *field.Field) bool {
+			fields = append(fields, *f)
+			return true
+		})
+	}
+
+	var message strings.Builder
+	for _, segment := range t.segments {
+		switch {
+		case segment.Placeholder < 0:
+			message.WriteString(segment.Literal)
+		case segment.Placeholder < len(args):
+			fmt.Fprint(&message, field.ResolveValue(args[segment.Placeholder]))
+		default:
+			message.WriteString("{" + t.Placeholders[segment.Placeholder] + "}")
+		}
+	}
+	excessive.WriteUnparsed(&message)
+
+	return message.String(), fields
+}
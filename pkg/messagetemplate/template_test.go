@@ -0,0 +1,59 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package messagetemplate
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute(t *testing.T) {
+	tpl := Parse("user {user_id} logged in from {ip} ({user_id}), {{literal}} {not a placeholder} {")
+	require.Equal(t, []string{"user_id", "ip"}, tpl.Placeholders)
+
+	message, fields := tpl.Execute(1, "127.0.0.1")
+	require.Equal(t, "user 1 logged in from 127.0.0.1 (1), {literal} {not a placeholder} {", message)
+	require.Equal(t, field.Fields{
+		{Key: "user_id", Value: 1},
+		{Key: "ip", Value: "127.0.0.1"},
+		{Key: FieldNameMessageTemplate, Value: tpl.Text},
+	}, fields)
+}
+
+func TestExecuteArgsMismatch(t *testing.T) {
+	tpl := Parse("user {user_id} logged in from {ip}")
+
+	message, fields := tpl.Execute(1)
+	require.Equal(t, "user 1 logged in from {ip}", message)
+	require.Equal(t, field.Fields{
+		{Key: "user_id", Value: 1},
+		{Key: FieldNameMessageTemplate, Value: tpl.Text},
+	}, fields)
+
+	args := []any{1, "127.0.0.1", "; extra", map[string]any{"attempt": 2}}
+	message, fields = tpl.Execute(args...)
+	require.Equal(t, "user 1 logged in from 127.0.0.1; extra", message)
+	require.Equal(t, field.Fields{
+		{Key: "user_id", Value: 1},
+		{Key: "ip", Value: "127.0.0.1"},
+		{Key: FieldNameMessageTemplate, Value: tpl.Text},
+		{Key: "attempt", Value: 2},
+	}, fields)
+	require.NotNil(t, args[3])
+}
+
+func TestParseCache(t *testing.T) {
+	require.Same(t, Parse("cached {value}"), Parse("cached {value}"))
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RedactHTTPRequest returns a shallow copy of the request with redacted
+// headers (including cookies, see RedactHeader) and URL (see RedactURL).
+// If there is nothing to redact, then the request is returned as is.
+//
+// The body and the parsed forms are not redacted.
+func (r *Redactor) RedactHTTPRequest(req *http.Request) *http.Request {
+	if req == nil {
+		return nil
+	}
+	header, headerChanged := r.redactHeader(req.Header)
+	u, urlChanged := r.redactURL(req.URL)
+	if !headerChanged && !urlChanged {
+		return req
+	}
+	result := *req
+	result.Header = header
+	result.URL = u
+	if urlChanged && result.RequestURI != "" {
+		result.RequestURI = u.RequestURI()
+	}
+	return &result
+}
+
+// RedactHeader returns a copy of the HTTP header with redacted values.
+// Values of sensitive headers are redacted completely, except header
+// "Cookie", where the values of cookies are redacted, but the names are preserved.
+// If there is nothing to redact, then the header is returned as is.
+func (r *Redactor) RedactHeader(header http.Header) http.Header {
+	result, _ := r.redactHeader(header)
+	return result
+}
+
+// RedactURL returns a copy of the URL with redacted password, path and
+// query parameters. If there is nothing to redact, then the URL is returned as is.
+func (r *Redactor) RedactURL(u *url.URL) *url.URL {
+	result, _ := r.redactURL(u)
+	return result
+}
+
+func (r *Redactor) redactHeader(header http.Header) (http.Header, bool) {
+	if len(header) == 0 {
+		return header, false
+	}
+	result := header.Clone()
+	if !r.redactMultiValues(result, true) {
+		return header, false
+	}
+	return result, true
+}
+
+func (r *Redactor) redactURL(u *url.URL) (*url.URL, bool) {
+	if u == nil {
+		return nil, false
+	}
+	var result *url.URL
+	clone := func() {
+		if result == nil {
+			cpy := *u
+			result = &cpy
+		}
+	}
+
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			clone()
+			result.User = url.UserPassword(u.User.Username(), r.Mask)
+		}
+	}
+	if path, keep, changed := r.redactString(u.Path); changed {
+		clone()
+		if !keep {
+			path = r.Mask
+		}
+		result.Path, result.RawPath = path, ""
+	}
+	if u.RawQuery != "" {
+		if query, err := url.ParseQuery(u.RawQuery); err == nil && r.redactMultiValues(query, false) {
+			clone()
+			result.RawQuery = query.Encode()
+		}
+	}
+
+	if result == nil {
+		return u, false
+	}
+	return result, true
+}
+
+// redactMultiValues redacts (in place) the values of a http.Header
+// or of url.Values. Returns true if anything was redacted.
+func (r *Redactor) redactMultiValues(m map[string][]string, isHeader bool) bool {
+	changed := false
+	for key, values := range m {
+		strategy, exempt := r.keyStrategy(key, nil)
+		switch {
+		case exempt:
+			continue
+		case strategy == StrategyDrop:
+			delete(m, key)
+			changed = true
+			continue
+		case strategy != StrategyUndefined:
+			for idx, value := range values {
+				if isHeader && key == "Cookie" {
+					values[idx] = r.redactCookies(strategy, value)
+				} else {
+					values[idx] = r.replacement(strategy, value)
+				}
+			}
+			changed = true
+			continue
+		}
+
+		for idx, value := range values {
+			redacted, keep, valueChanged := r.redactString(value)
+			if !valueChanged {
+				continue
+			}
+			changed = true
+			if !keep {
+				delete(m, key)
+				break
+			}
+			values[idx] = redacted
+		}
+	}
+	return changed
+}
+
+func (r *Redactor) redactCookies(strategy Strategy, header string) string {
+	cookies := strings.Split(header, ";")
+	for idx, cookie := range cookies {
+		name, value, _ := strings.Cut(strings.TrimSpace(cookie), "=")
+		cookies[idx] = name + "=" + r.replacement(strategy, value)
+	}
+	return strings.Join(cookies, "; ")
+}
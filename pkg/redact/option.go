@@ -0,0 +1,79 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"fmt"
+
+	"github.com/facebookincubator/go-belt/pkg/valuesparser"
+)
+
+// Option is an optional argument to function New, that changes the behavior of the Redactor.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		Rules:    DefaultRules(),
+		Strategy: StrategyMask,
+		Mask:     fmt.Sprint(valuesparser.RedactedValue),
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	Rules    []Rule
+	Strategy Strategy
+	Mask     string
+	HashKey  []byte
+}
+
+// OptionRules overrides the Rules (see DefaultRules). To extend the default
+// rules use for example:
+//
+//	redact.OptionRules(append(redact.DefaultRules(), myRule))
+type OptionRules []Rule
+
+func (opt OptionRules) apply(cfg *config) {
+	cfg.Rules = opt
+}
+
+// OptionStrategy overrides the default Strategy (which is StrategyMask).
+type OptionStrategy Strategy
+
+func (opt OptionStrategy) apply(cfg *config) {
+	cfg.Strategy = Strategy(opt)
+}
+
+// OptionMask overrides the value used by StrategyMask
+// (which is valuesparser.RedactedValue by default).
+type OptionMask string
+
+func (opt OptionMask) apply(cfg *config) {
+	cfg.Mask = string(opt)
+}
+
+// OptionHashKey sets the key of the HMAC used by StrategyHash. If it is not
+// set, then a random key is generated once per process, so the hashes
+// could be correlated only within the same process.
+type OptionHashKey []byte
+
+func (opt OptionHashKey) apply(cfg *config) {
+	cfg.HashKey = opt
+}
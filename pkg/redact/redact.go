@@ -0,0 +1,239 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package redact implements detection and redaction of sensitive data
+// (passwords, tokens, e-mails, card numbers and so on) in fields.
+//
+// It is tool-agnostic, see the Hooks which redact logger entries, tracer
+// spans and error monitor events in:
+//
+//	github.com/facebookincubator/go-belt/tool/logger/hooks/redact
+//	github.com/facebookincubator/go-belt/tool/experimental/tracer/hooks/redact
+//	github.com/facebookincubator/go-belt/tool/experimental/errmon/hooks/redact
+//
+// A field is considered sensitive if it is marked with FieldPropSensitive
+// (or with a Strategy as a field.Property) or if its key matches a Rule.
+// Otherwise the values which are strings, []byte-s, errors or fmt.Stringer-s
+// are scanned for sensitive substrings (like e-mails), lazy values (see
+// field.LazyValue) are resolved first. If anything matched, then the value
+// is replaced with the redacted string. Other values (including structures,
+// which are parsed by Emitters later) are passed as is: use tag
+// `log:",redact"` for fields of structures (see valuesparser.Tag).
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+)
+
+// Redactor detects and redacts sensitive data.
+type Redactor struct {
+	Rules    []Rule
+	Strategy Strategy
+	Mask     string
+	HashKey  []byte
+}
+
+// New returns a new instance of Redactor.
+func New(opts ...Option) *Redactor {
+	cfg := options(opts).Config()
+	return &Redactor{
+		Rules:    cfg.Rules,
+		Strategy: cfg.Strategy,
+		Mask:     cfg.Mask,
+		HashKey:  cfg.HashKey,
+	}
+}
+
+// Redact returns the redacted copy of the field and false if
+// the field should be dropped.
+func (r *Redactor) Redact(f *field.Field) (field.Field, bool) {
+	result, keep, _ := r.redact(f)
+	return result, keep
+}
+
+// RedactFields returns the redacted fields and true if anything was
+// redacted. If there was nothing to redact, then the fields are returned as is.
+func (r *Redactor) RedactFields(fields field.AbstractFields) (field.AbstractFields, bool) {
+	if fields == nil || !r.needsRedacting(fields) {
+		return fields, false
+	}
+	result := make(field.Fields, 0, fields.Len())
+	fields.ForEachField(func(f *field.Field) bool {
+		redacted, keep, _ := r.redact(f)
+		if keep {
+			result = append(result, redacted)
+		}
+		return true
+	})
+	return result, true
+}
+
+// RedactString redacts sensitive substrings (see Rule.Value). If
+// a matched Rule requires to drop the value, then the whole string is masked.
+func (r *Redactor) RedactString(s string) string {
+	result, keep, _ := r.redactString(s)
+	if !keep {
+		return r.Mask
+	}
+	return result
+}
+
+// Hash returns the representation of the value used by StrategyHash.
+//
+// It is an HMAC keyed with HashKey. If HashKey is empty, then a random key
+// is used, which is generated once per process (see OptionHashKey).
+func (r *Redactor) Hash(s string) string {
+	key := r.HashKey
+	if len(key) == 0 {
+		key = processHashKey()
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(s))
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// processHashKey returns the key used by Hash if HashKey is not set. An
+// unkeyed hash would allow to recover values from a small set (like
+// card numbers or e-mails) by a brute force.
+var processHashKey = sync.OnceValue(func() []byte {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Errorf("unable to generate a random key: %w", err))
+	}
+	return key
+})
+
+func (r *Redactor) needsRedacting(fields field.AbstractFields) bool {
+	result := false
+	fields.ForEachField(func(f *field.Field) bool {
+		_, _, result = r.redact(f)
+		return !result
+	})
+	return result
+}
+
+func (r *Redactor) redact(f *field.Field) (result field.Field, keep bool, changed bool) {
+	result = *f
+	strategy, exempt := r.keyStrategy(f.Key, f.Properties)
+	switch {
+	case exempt:
+		return result, true, false
+	case strategy == StrategyDrop:
+		return result, false, true
+	case strategy == StrategyHash:
+		result.Value = r.Hash(fmt.Sprint(field.ResolveValue(f.Value)))
+		return result, true, true
+	case strategy != StrategyUndefined:
+		result.Value = r.Mask
+		return result, true, true
+	}
+
+	s, ok := valueString(field.ResolveValue(f.Value))
+	if !ok {
+		return result, true, false
+	}
+	s, keep, changed = r.redactString(s)
+	if changed {
+		result.Value = s
+	}
+	return result, keep, changed
+}
+
+// valueString returns the string form of a value which should be scanned
+// for sensitive substrings, or false if the value is not scanned.
+func valueString(value field.Value) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case []byte:
+		return string(value), true
+	case error, fmt.Stringer:
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", false
+		}
+		if err, ok := value.(error); ok {
+			return err.Error(), true
+		}
+		return value.(fmt.Stringer).String(), true
+	}
+	return "", false
+}
+
+// keyStrategy returns the Strategy to redact the whole value of a field
+// with the given key and properties; or StrategyUndefined if the value
+// should be scanned for sensitive substrings instead. It returns true
+// if the field is exempted from redaction.
+func (r *Redactor) keyStrategy(key field.Key, props field.Properties) (Strategy, bool) {
+	for _, prop := range props {
+		switch prop := prop.(type) {
+		case fieldProperty:
+			switch prop {
+			case FieldPropNotSensitive:
+				return StrategyUndefined, true
+			case FieldPropSensitive:
+				return r.Strategy, false
+			}
+		case Strategy:
+			return r.strategy(prop), false
+		}
+	}
+	for idx := range r.Rules {
+		rule := &r.Rules[idx]
+		if rule.matchKey(key) {
+			return r.strategy(rule.Strategy), false
+		}
+	}
+	return StrategyUndefined, false
+}
+
+func (r *Redactor) strategy(strategy Strategy) Strategy {
+	if strategy == StrategyUndefined {
+		return r.Strategy
+	}
+	return strategy
+}
+
+func (r *Redactor) replacement(strategy Strategy, s string) string {
+	if strategy == StrategyHash {
+		return r.Hash(s)
+	}
+	return r.Mask
+}
+
+// redactString returns the redacted string, false if the value should be
+// dropped and true if anything was redacted.
+func (r *Redactor) redactString(s string) (string, bool, bool) {
+	changed := false
+	for idx := range r.Rules {
+		rule := &r.Rules[idx]
+		if !rule.matchValue(s) {
+			continue
+		}
+		changed = true
+		strategy := r.strategy(rule.Strategy)
+		if strategy == StrategyDrop {
+			return "", false, true
+		}
+		s = rule.replaceValue(s, func(match string) string {
+			return r.replacement(strategy, match)
+		})
+	}
+	return s, true, changed
+}
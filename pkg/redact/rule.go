@@ -0,0 +1,168 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"regexp"
+)
+
+// Strategy defines how a sensitive value is redacted.
+//
+// A Strategy may also be used as a field.Property to mark a specific field
+// as sensitive and to choose how exactly it should be redacted.
+type Strategy uint
+
+const (
+	// StrategyUndefined means "use the default Strategy of the Redactor".
+	StrategyUndefined = Strategy(iota)
+
+	// StrategyMask replaces the value with the mask (see OptionMask).
+	StrategyMask
+
+	// StrategyHash replaces the value with its hash (see OptionHashKey),
+	// so that equal values could still be correlated.
+	StrategyHash
+
+	// StrategyDrop removes the field completely.
+	StrategyDrop
+)
+
+// String implements fmt.Stringer.
+func (s Strategy) String() string {
+	switch s {
+	case StrategyUndefined:
+		return "undefined"
+	case StrategyMask:
+		return "mask"
+	case StrategyHash:
+		return "hash"
+	case StrategyDrop:
+		return "drop"
+	default:
+		return "unknown"
+	}
+}
+
+type fieldProperty uint
+
+const (
+	fieldPropertyUndefined = fieldProperty(iota) //nolint:deadcode,unused,varcheck
+
+	// FieldPropSensitive is a field.Property which marks the field as sensitive,
+	// it is redacted using the default Strategy of the Redactor.
+	FieldPropSensitive
+
+	// FieldPropNotSensitive is a field.Property which exempts the field
+	// from redaction (for example if a key matches a Rule by accident,
+	// like "tokens_count").
+	FieldPropNotSensitive
+)
+
+// Rule defines which data is considered sensitive.
+//
+// Exactly one of Key and Value is supposed to be set.
+type Rule struct {
+	// Key is matched against field keys (as well as names of HTTP headers
+	// and URL query parameters). The whole value of a matched field is redacted.
+	Key *regexp.Regexp
+
+	// Value is matched against string values. Only the matched
+	// substrings are redacted (unless the Strategy is StrategyDrop,
+	// in which case the whole field is dropped).
+	Value *regexp.Regexp
+
+	// Validate (if not nil) filters matches of Value, only substrings
+	// for which it returns true are redacted. It is used to reduce
+	// false positives (see for example ValidLuhn).
+	Validate func(string) bool
+
+	// Strategy overrides the default Strategy of the Redactor for this Rule.
+	Strategy Strategy
+}
+
+var (
+	// RuleSecretKeys matches keys which usually contain credentials.
+	RuleSecretKeys = Rule{
+		Key: regexp.MustCompile(`(?i)passw(or)?d|secret|token|authorization|api[-_]?key|cookie|session|credential|private[-_]?key`),
+	}
+
+	// RuleEmails matches e-mail addresses in values.
+	RuleEmails = Rule{
+		Value: regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`),
+	}
+
+	// RuleCardNumbers matches payment card numbers (digits optionally
+	// separated by spaces or dashes, which pass the Luhn check) in values.
+	RuleCardNumbers = Rule{
+		Value:    regexp.MustCompile(`\b\d(?:[ -]?\d){11,18}\b`),
+		Validate: ValidLuhn,
+	}
+)
+
+// DefaultRules returns the Rules used if OptionRules is not specified.
+func DefaultRules() []Rule {
+	return []Rule{RuleSecretKeys, RuleEmails, RuleCardNumbers}
+}
+
+// ValidLuhn returns true if the digits of the string pass the Luhn check.
+// Non-digit characters are ignored.
+func ValidLuhn(s string) bool {
+	sum := 0
+	count := 0
+	double := false
+	for idx := len(s) - 1; idx >= 0; idx-- {
+		c := s[idx]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		count++
+		double = !double
+	}
+	return count > 1 && sum%10 == 0
+}
+
+func (rule *Rule) matchKey(key string) bool {
+	return rule.Key != nil && rule.Key.MatchString(key)
+}
+
+func (rule *Rule) matchValue(s string) bool {
+	if rule.Value == nil {
+		return false
+	}
+	if rule.Validate == nil {
+		return rule.Value.MatchString(s)
+	}
+	for _, match := range rule.Value.FindAllString(s, -1) {
+		if rule.Validate(match) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule *Rule) replaceValue(s string, replace func(string) string) string {
+	return rule.Value.ReplaceAllStringFunc(s, func(match string) string {
+		if rule.Validate != nil && !rule.Validate(match) {
+			return match
+		}
+		return replace(match)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 var (
@@ -64,8 +65,58 @@ var DefaultCallerPCFilter PCFilter = func(pc uintptr) bool {
 	funcName := fn.Name()
 	switch {
 	case strings.Contains(funcName, "github.com/facebookincubator/go-belt"),
-		strings.HasPrefix(funcName, "runtime"):
+		strings.HasPrefix(funcName, "runtime"),
+		isSkippedCallerFunc(funcName):
 		return false
 	}
 	return true
 }
+
+var skippedCallerPackages atomic.Pointer[[]string]
+
+// SkipCallerPackages makes DefaultCallerPCFilter to skip functions of the given
+// packages (and their subpackages).
+//
+// It is used by bridges from third-party logging libraries to a Logger,
+// to report the code which invoked the library as the caller, instead
+// of the library itself.
+func SkipCallerPackages(pkgPaths ...string) {
+	for {
+		oldPtr := skippedCallerPackages.Load()
+		var old []string
+		if oldPtr != nil {
+			old = *oldPtr
+		}
+		pkgs := make([]string, len(old), len(old)+len(pkgPaths))
+		copy(pkgs, old)
+	nextPkg:
+		for _, pkgPath := range pkgPaths {
+			for _, pkg := range pkgs {
+				if pkg == pkgPath {
+					continue nextPkg
+				}
+			}
+			pkgs = append(pkgs, pkgPath)
+		}
+		if skippedCallerPackages.CompareAndSwap(oldPtr, &pkgs) {
+			return
+		}
+	}
+}
+
+func isSkippedCallerFunc(funcName string) bool {
+	pkgs := skippedCallerPackages.Load()
+	if pkgs == nil {
+		return false
+	}
+	for _, pkgPath := range *pkgs {
+		if !strings.HasPrefix(funcName, pkgPath) || len(funcName) == len(pkgPath) {
+			continue
+		}
+		switch funcName[len(pkgPath)] {
+		case '.', '/':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package valuesparser
+
+import (
+	"strings"
+)
+
+// Tag is a parsed struct tag `log`.
+//
+// The format is similar to the format of tag `json`: the name of
+// the field followed by comma-separated options, for example:
+//
+//	UserID    string            `log:"user_id"`
+//	Password  string            `log:",redact"`
+//	Labels    map[string]string `log:",inline,omitempty"`
+//	Internal  string            `log:"-"`
+type Tag struct {
+	// Name overrides the name of the field (if not empty).
+	Name string
+
+	// Skip is true if the field should not be logged (tag `log:"-"`).
+	Skip bool
+
+	// OmitEmpty skips empty slices and maps (zero values are always skipped).
+	OmitEmpty bool
+
+	// Inline flattens a nested structure or map: its fields are
+	// provided without the prefix of the parent field.
+	Inline bool
+
+	// Redact replaces the value with RedactedValue.
+	Redact bool
+
+	// String provides the value as a string: using fmt.Stringer if
+	// it is implemented (otherwise using fmt.Sprint).
+	String bool
+}
+
+// ParseTag parses the value of struct tag `log`.
+func ParseTag(tag string) Tag {
+	if tag == "-" {
+		return Tag{Skip: true}
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	result := Tag{Name: name}
+	for options != "" {
+		var option string
+		option, options, _ = strings.Cut(options, ",")
+		switch strings.TrimSpace(option) {
+		case "omitempty":
+			result.OmitEmpty = true
+		case "inline":
+			result.Inline = true
+		case "redact":
+			result.Redact = true
+		case "string":
+			result.String = true
+		}
+	}
+	return result
+}
@@ -16,11 +16,14 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/facebookincubator/go-belt/pkg/field"
 )
 
+var lazyValueType = reflect.TypeOf((*field.LazyValue)(nil))
+
 // AnySlice a handler for arbitrary values, which extracts structured fields/values
 // by method ForEachField() and provides everything else as a string by method WriteUnparsed.
 //
@@ -28,6 +31,9 @@ import (
 // of structured fields right after just a type-casting ([]any -> AnySlice). It is
 // supposed to be a zero allocation implementation in future.
 //
+// Errors are provided as the set of fields returned by field.ForEachErrorField
+// (with key field.ErrorKey), including the fields of the wrapped errors.
+//
 // For example it is used to parse all the arguments of logger.Logger.Log.
 type AnySlice []any
 
@@ -42,18 +48,31 @@ func (p *AnySlice) ForEachField(callback func(f *field.Field) bool) bool {
 		}
 
 		switch v := value.(type) {
+		case *field.LazyValue, func() field.Value:
+			// is a part of the unstructured message, see WriteUnparsed
+			idx++
+			continue
+		case error:
+			r := field.ForEachErrorField(field.ErrorKey, v, callback)
+			(*p)[idx] = nil
+			if !r {
+				return false
+			}
+			continue
 		case field.ForEachFieldser:
-			if !v.ForEachField(callback) {
+			r := v.ForEachField(callback)
+			if isStruct(value) {
+				// A structure is consumed the same way regardless whether
+				// it implements ForEachFieldser (for example through methods
+				// generated by cmd/fieldsgen) or is parsed by ParseStructValue.
+				(*p)[idx] = nil
+			} else {
+				idx++
+			}
+			if !r {
 				return false
 			}
-			idx++
 			continue
-		case error:
-			callback(&field.Field{
-				Key:        "error",
-				Value:      v,
-				Properties: nil,
-			})
 		}
 
 		v := reflect.Indirect(reflect.ValueOf(value))
@@ -78,18 +97,26 @@ func (p *AnySlice) ForEachField(callback func(f *field.Field) bool) bool {
 	return true
 }
 
+func isStruct(value any) bool {
+	return reflect.Indirect(reflect.ValueOf(value)).Kind() == reflect.Struct
+}
+
 // ParseMapValue calls the callback for each pair in the map until first false is returned
 //
 // It returns false if callback returned false.
 func ParseMapValue(m reflect.Value, callback func(f *field.Field) bool) bool {
+	return parseMapValue("", m, callback)
+}
+
+func parseMapValue(keyPrefix string, m reflect.Value, callback func(f *field.Field) bool) bool {
 	var f field.Field
 	for _, keyV := range m.MapKeys() {
 		valueV := m.MapIndex(keyV)
 		switch key := keyV.Interface().(type) {
 		case field.Key:
-			f.Key = key
+			f.Key = keyPrefix + key
 		default:
-			f.Key = fmt.Sprint(key)
+			f.Key = keyPrefix + fmt.Sprint(key)
 		}
 		f.Value = valueV.Interface()
 		if !callback(&f) {
@@ -99,22 +126,47 @@ func ParseMapValue(m reflect.Value, callback func(f *field.Field) bool) bool {
 	return true
 }
 
+var (
+	// RedactedValue is the value provided instead of the values
+	// of fields tagged with option "redact".
+	RedactedValue field.Value = "<redacted>"
+
+	// MaxDepth is the maximal depth of nested structures parsed by ParseStructValue.
+	// Deeper structures are skipped.
+	MaxDepth = 32
+)
+
 // ParseStructValue parses a structure to a collection of fields.
 //
 // `fieldPath` is the prefix of the field-name.
 // `_struct` is the structure to be parsed (provided as a reflect.Value).
 // `callback` is the function called for each found field, until first false is returned.
 //
+// The fields are named and processed according to the struct tag `log`, see Tag.
+// Recursive structures are parsed until MaxDepth or until a cycle is detected.
+//
 // It returns false if callback returned false.
 func ParseStructValue(fieldPath []string, _struct reflect.Value, callback func(f *field.Field) bool) bool {
+	var parents []uintptr
+	if _struct.Kind() == reflect.Pointer {
+		parents = append(parents, _struct.Pointer())
+	}
+	return parseStructValue(fieldPath, _struct, callback, 0, parents)
+}
+
+// parseStructValue is the implementation of ParseStructValue, `parents` are
+// the pointers to the parent structures (to detect cycles).
+func parseStructValue(fieldPath []string, _struct reflect.Value, callback func(f *field.Field) bool, depth int, parents []uintptr) bool {
+	if depth > MaxDepth {
+		return true
+	}
+
 	s := reflect.Indirect(_struct)
 
 	var f field.Field
 	// TODO: optimize this
 	t := s.Type()
 
-	fieldPath = append(fieldPath, "")
-
 	fieldCount := s.NumField()
 	for fieldNum := 0; fieldNum < fieldCount; fieldNum++ {
 		structFieldType := t.Field(fieldNum)
@@ -122,30 +174,68 @@ func ParseStructValue(fieldPath []string, _struct reflect.Value, callback func(f
 			// unexported
 			continue
 		}
-		logTag := structFieldType.Tag.Get("log")
-		if logTag == "-" {
+		tag := ParseTag(structFieldType.Tag.Get("log"))
+		if tag.Skip {
 			continue
 		}
 		structField := s.Field(fieldNum)
 		if structField.IsZero() {
 			continue
 		}
+		if tag.OmitEmpty {
+			switch structField.Kind() {
+			case reflect.Slice, reflect.Map:
+				if structField.Len() == 0 {
+					continue
+				}
+			}
+		}
 		value := reflect.Indirect(structField)
+		if structFieldType.Type == lazyValueType {
+			// is computed only if it is actually used, so it is passed as is
+			value = structField
+		}
 
 		pathComponent := structFieldType.Name
-		if logTag != "" {
-			pathComponent = logTag
+		if tag.Name != "" {
+			pathComponent = tag.Name
+		}
+		path := append(fieldPath[:len(fieldPath):len(fieldPath)], pathComponent)
+		nestedPath := path
+		if tag.Inline {
+			nestedPath = fieldPath
 		}
-		fieldPath[len(fieldPath)-1] = pathComponent
 
-		if value.Kind() == reflect.Struct {
-			if !ParseStructValue(fieldPath, value, callback) {
+		switch {
+		case tag.Redact:
+			value = reflect.ValueOf(RedactedValue)
+		case tag.String:
+			value = reflect.ValueOf(stringValue(structField))
+		case value.Kind() == reflect.Struct:
+			nestedParents := parents
+			if structField.Kind() == reflect.Pointer {
+				if slices.Contains(parents, structField.Pointer()) {
+					// a cycle
+					continue
+				}
+				nestedParents = append(parents[:len(parents):len(parents)], structField.Pointer())
+			}
+			if !parseStructValue(nestedPath, value, callback, depth+1, nestedParents) {
+				return false
+			}
+			continue
+		case tag.Inline && value.Kind() == reflect.Map:
+			keyPrefix := strings.Join(nestedPath, ".")
+			if keyPrefix != "" {
+				keyPrefix += "."
+			}
+			if !parseMapValue(keyPrefix, value, callback) {
 				return false
 			}
 			continue
 		}
 
-		f.Key = strings.Join(fieldPath, ".")
+		f.Key = strings.Join(path, ".")
 		f.Value = value.Interface()
 		if !callback(&f) {
 			return false
@@ -155,6 +245,20 @@ func ParseStructValue(fieldPath []string, _struct reflect.Value, callback func(f
 	return true
 }
 
+// stringValue returns the value as a string, using fmt.Stringer if
+// it is implemented by the value or by the pointer to the value.
+func stringValue(v reflect.Value) string {
+	if stringer, ok := v.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	if v.CanAddr() {
+		if stringer, ok := v.Addr().Interface().(fmt.Stringer); ok {
+			return stringer.String()
+		}
+	}
+	return fmt.Sprint(reflect.Indirect(v).Interface())
+}
+
 // Len implements field.AbstractFields.
 func (p *AnySlice) Len() int {
 	return len(*p)
@@ -168,6 +272,6 @@ func (p *AnySlice) WriteUnparsed(w io.Writer) {
 			continue
 		}
 
-		fmt.Fprint(w, value)
+		fmt.Fprint(w, field.ResolveValue(value))
 	}
 }
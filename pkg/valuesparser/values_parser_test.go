@@ -14,7 +14,9 @@ package valuesparser
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/facebookincubator/go-belt/pkg/field"
@@ -24,16 +26,173 @@ import (
 func TestAnySliceError(t *testing.T) {
 	err := fmt.Errorf("unit-test")
 	s := AnySlice{err}
-	count := 0
+	var fields field.Fields
 	r := s.ForEachField(func(f *field.Field) bool {
-		count++
-		assert.Equal(t, f.Key, "error")
+		fields = append(fields, *f)
 		return true
 	})
 	assert.True(t, r)
-	assert.Equal(t, count, 1)
+	assert.Equal(t, field.Fields{
+		{Key: "error", Value: err},
+		{Key: "error.type", Value: "*errors.errorString"},
+		{Key: "error.chain", Value: []string{"*errors.errorString"}},
+	}, fields)
 
 	var buf bytes.Buffer
 	s.WriteUnparsed(&buf)
 	assert.Equal(t, "", buf.String())
 }
+
+type fieldsError struct {
+	field.Fields
+}
+
+func (fieldsError) Error() string {
+	return "fields error"
+}
+
+func TestAnySliceWrappedError(t *testing.T) {
+	inner := fieldsError{Fields: field.Fields{{Key: "user_id", Value: 1}}}
+	err := fmt.Errorf("unable to process: %w", errors.Join(errors.New("first"), inner))
+	s := AnySlice{"some message", err}
+	var fields field.Fields
+	s.ForEachField(func(f *field.Field) bool {
+		fields = append(fields, *f)
+		return true
+	})
+	assert.Equal(t, field.Fields{
+		{Key: "error", Value: err},
+		{Key: "error.type", Value: "*fmt.wrapError"},
+		{Key: "error.chain", Value: []string{"*fmt.wrapError", "*errors.joinError", "*errors.errorString", "valuesparser.fieldsError"}},
+		{Key: "user_id", Value: 1},
+	}, fields)
+
+	var buf bytes.Buffer
+	s.WriteUnparsed(&buf)
+	assert.Equal(t, "some message", buf.String())
+}
+
+func TestAnySliceLazy(t *testing.T) {
+	calls := 0
+	lazy := field.Lazy(func() any {
+		calls++
+		return "value"
+	})
+	s := AnySlice{"some ", lazy, struct{ Lazy *field.LazyValue }{Lazy: lazy}}
+	var fields field.Fields
+	s.ForEachField(func(f *field.Field) bool {
+		fields = append(fields, *f)
+		return true
+	})
+	assert.Equal(t, field.Fields{{Key: "Lazy", Value: lazy}}, fields)
+	assert.Equal(t, 0, calls)
+
+	var buf bytes.Buffer
+	s.WriteUnparsed(&buf)
+	assert.Equal(t, "some value", buf.String())
+	assert.Equal(t, 1, calls)
+}
+
+type fieldserStruct struct {
+	UserID int
+}
+
+func (s fieldserStruct) ForEachField(callback func(f *field.Field) bool) bool {
+	return callback(&field.Field{Key: "user_id", Value: s.UserID})
+}
+
+func TestAnySliceForEachFieldser(t *testing.T) {
+	s := AnySlice{"some message ", field.Fields{{Key: "user_id", Value: 1}}, &fieldserStruct{UserID: 2}}
+	var fields field.Fields
+	s.ForEachField(func(f *field.Field) bool {
+		fields = append(fields, *f)
+		return true
+	})
+	assert.Equal(t, field.Fields{{Key: "user_id", Value: 1}, {Key: "user_id", Value: 2}}, fields)
+
+	// structures are consumed the same way as if they were parsed by ParseStructValue
+	var buf bytes.Buffer
+	s.WriteUnparsed(&buf)
+	assert.Equal(t, "some message [{user_id 1 []}]", buf.String())
+}
+
+type testStringer struct {
+	Value int
+}
+
+func (s *testStringer) String() string {
+	return fmt.Sprintf("value:%d", s.Value)
+}
+
+func TestParseStructValueTags(t *testing.T) {
+	type nested struct {
+		Region string `log:"region"`
+	}
+	value := struct {
+		Password string            `log:"password,redact"`
+		Labels   map[string]string `log:"label,inline"`
+		Empty    []string          `log:"empty,omitempty"`
+		NotEmpty []string          `log:"not_empty,omitempty"`
+		Nested   nested            `log:",inline"`
+		Stringer testStringer      `log:"stringer,string"`
+		Number   *int              `log:"number,string"`
+	}{
+		Password: "secret",
+		Labels:   map[string]string{"k": "v"},
+		Empty:    []string{},
+		NotEmpty: []string{"a"},
+		Nested:   nested{Region: "eu"},
+		Stringer: testStringer{Value: 1},
+		Number:   &[]int{2}[0],
+	}
+
+	var fields field.Fields
+	r := ParseStructValue(nil, reflect.ValueOf(&value), func(f *field.Field) bool {
+		fields = append(fields, *f)
+		return true
+	})
+	assert.True(t, r)
+	assert.Equal(t, field.Fields{
+		{Key: "password", Value: RedactedValue},
+		{Key: "k", Value: "v"},
+		{Key: "not_empty", Value: []string{"a"}},
+		{Key: "region", Value: "eu"},
+		{Key: "stringer", Value: "value:1"},
+		{Key: "number", Value: "2"},
+	}, fields)
+}
+
+type testNode struct {
+	Name string
+	Next *testNode
+}
+
+func TestParseStructValueCycle(t *testing.T) {
+	a := &testNode{Name: "a"}
+	b := &testNode{Name: "b", Next: a}
+	a.Next = b
+
+	var fields field.Fields
+	ParseStructValue(nil, reflect.ValueOf(a), func(f *field.Field) bool {
+		fields = append(fields, *f)
+		return true
+	})
+	assert.Equal(t, field.Fields{
+		{Key: "Name", Value: "a"},
+		{Key: "Next.Name", Value: "b"},
+	}, fields)
+
+	oldMaxDepth := MaxDepth
+	defer func() { MaxDepth = oldMaxDepth }()
+	MaxDepth = 1
+	c := &testNode{Name: "c", Next: &testNode{Name: "d", Next: &testNode{Name: "e"}}}
+	fields = fields[:0]
+	ParseStructValue(nil, reflect.ValueOf(c), func(f *field.Field) bool {
+		fields = append(fields, *f)
+		return true
+	})
+	assert.Equal(t, field.Fields{
+		{Key: "Name", Value: "c"},
+		{Key: "Next.Name", Value: "d"},
+	}, fields)
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/errors"
 	"github.com/facebookincubator/go-belt/pkg/field"
 	"github.com/facebookincubator/go-belt/pkg/runtime"
 	errmontypes "github.com/facebookincubator/go-belt/tool/experimental/errmon/types"
@@ -122,6 +123,10 @@ func (h *GenericErrorMonitor) ObserveError(belt *belt.Belt, err error) *Event {
 		return nil
 	}
 	ev.Exception.Error = err
+	if stackTrace := errors.StackTrace(err); stackTrace != nil {
+		// the place where the error was created is more useful than the place where it was observed
+		ev.Exception.StackTrace = stackTrace
+	}
 	ev.Entry.Level = loggertypes.LevelError
 	ev.Entry.Properties = []loggertypes.EntryProperty{errmontypes.EntryPropertyErrorMonitoringEventEntry, errmontypes.EntryPropertyErrorEvent}
 	if !h.Hooks.Process(ev) {
@@ -0,0 +1,96 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"net/http"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/redact"
+	errmontypes "github.com/facebookincubator/go-belt/tool/experimental/errmon/types"
+)
+
+// Hook is an errmon.Hook implementation which redacts sensitive data in
+// messages and fields of Events (see package redact). Fields with
+// HTTPRequest values are replaced with redacted copies of the requests
+// (headers, cookies and URL, see redact.Redactor.RedactHTTPRequest).
+//
+// The error and the panic value of an Event are not modified.
+//
+// Setup example:
+//
+//	import (
+//		"github.com/facebookincubator/go-belt/pkg/redact"
+//		redacthook "github.com/facebookincubator/go-belt/tool/experimental/errmon/hooks/redact"
+//		"github.com/facebookincubator/go-belt/tool/experimental/errmon/implementation/sentry"
+//	)
+//
+//	func main() {
+//		...
+//		ctx = errmon.CtxWithErrorMonitor(ctx, sentry.New(sentryClient).WithHooks(
+//			redacthook.New(redact.New()),
+//		))
+//		...
+//	}
+type Hook struct {
+	Redactor *redact.Redactor
+}
+
+var _ errmontypes.Hook = (*Hook)(nil)
+
+// New returns a new instance of Hook.
+func New(redactor *redact.Redactor) *Hook {
+	return &Hook{
+		Redactor: redactor,
+	}
+}
+
+// Process implements errmon.Hook.
+func (hook *Hook) Process(ev *errmontypes.Event) bool {
+	ev.Message = hook.Redactor.RedactString(ev.Message)
+	if ev.Fields == nil {
+		return true
+	}
+	ev.Fields, _ = hook.Redactor.RedactFields(hook.redactHTTPRequests(ev.Fields))
+	return true
+}
+
+func (hook *Hook) redactHTTPRequests(fields field.AbstractFields) field.AbstractFields {
+	hasRequests := false
+	fields.ForEachField(func(f *field.Field) bool {
+		switch f.Value.(type) {
+		case errmontypes.HTTPRequest, *errmontypes.HTTPRequest:
+			hasRequests = true
+			return false
+		}
+		return true
+	})
+	if !hasRequests {
+		return fields
+	}
+
+	result := field.Gather(fields)
+	for idx := range result {
+		var req *http.Request
+		switch value := result[idx].Value.(type) {
+		case errmontypes.HTTPRequest:
+			req = (*http.Request)(&value)
+		case *errmontypes.HTTPRequest:
+			req = (*http.Request)(value)
+		default:
+			continue
+		}
+		result[idx].Value = (*errmontypes.HTTPRequest)(hook.Redactor.RedactHTTPRequest(req))
+	}
+	return result
+}
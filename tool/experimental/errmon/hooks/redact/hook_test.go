@@ -0,0 +1,50 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/redact"
+	errmontypes "github.com/facebookincubator/go-belt/tool/experimental/errmon/types"
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/login?api_key=abc", nil)
+	require.NoError(t, err)
+	req.Header.Set("Cookie", "sid=abc")
+
+	ev := &errmontypes.Event{
+		Entry: logger.Entry{
+			Message: "unable to notify john.doe@example.com",
+			Fields: field.Fields{
+				{Key: "request", Value: (*errmontypes.HTTPRequest)(req)},
+				{Key: "password", Value: "qwerty"},
+			},
+		},
+	}
+	require.True(t, New(redact.New()).Process(ev))
+	require.Equal(t, "unable to notify <redacted>", ev.Message)
+
+	fields := field.Gather(ev.Fields)
+	require.Len(t, fields, 2)
+	redactedReq := fields[0].Value.(*errmontypes.HTTPRequest)
+	require.Equal(t, "sid=<redacted>", redactedReq.Header.Get("Cookie"))
+	require.Equal(t, "api_key=%3Credacted%3E", redactedReq.URL.RawQuery)
+	require.Equal(t, "sid=abc", req.Header.Get("Cookie"))
+	require.Equal(t, field.Field{Key: "password", Value: "<redacted>"}, fields[1])
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/redact"
+	"github.com/facebookincubator/go-belt/tool/experimental/tracer"
+)
+
+// Hook is a tracer.Hook implementation which redacts sensitive data in
+// names and fields of Spans (see package redact), the fields are
+// overwritten using Span.SetField. Since a Span does not allow
+// to remove a field, dropped fields are masked instead.
+//
+// Setup example:
+//
+//	import (
+//		"github.com/facebookincubator/go-belt/pkg/redact"
+//		redacthook "github.com/facebookincubator/go-belt/tool/experimental/tracer/hooks/redact"
+//		"github.com/facebookincubator/go-belt/tool/experimental/tracer/implementation/zipkin"
+//	)
+//
+//	func main() {
+//		...
+//		ctx = tracer.CtxWithTracer(ctx, zipkin.New(zipkinClient).WithHooks(
+//			redacthook.New(redact.New()),
+//		))
+//		...
+//	}
+type Hook struct {
+	Redactor *redact.Redactor
+}
+
+var _ tracer.Hook = (*Hook)(nil)
+
+// New returns a new instance of Hook.
+func New(redactor *redact.Redactor) *Hook {
+	return &Hook{
+		Redactor: redactor,
+	}
+}
+
+// ProcessSpan implements tracer.Hook.
+func (hook *Hook) ProcessSpan(span tracer.Span) bool {
+	if name := hook.Redactor.RedactString(span.Name()); name != span.Name() {
+		span.SetName(name)
+	}
+
+	fields := span.Fields()
+	if fields == nil {
+		return true
+	}
+	if _, changed := hook.Redactor.RedactFields(fields); !changed {
+		return true
+	}
+
+	// Fields may contain outdated values of the same key (the latest value
+	// goes first), thus only the first value of each key is processed.
+	var updates field.Fields
+	seen := map[field.Key]struct{}{}
+	fields.ForEachField(func(f *field.Field) bool {
+		if _, ok := seen[f.Key]; ok {
+			return true
+		}
+		seen[f.Key] = struct{}{}
+		redacted, changed := hook.Redactor.RedactFields(f)
+		if !changed {
+			return true
+		}
+		if redacted.Len() == 0 {
+			updates = append(updates, field.Field{Key: f.Key, Value: hook.Redactor.Mask})
+			return true
+		}
+		updates = append(updates, field.Gather(redacted)...)
+		return true
+	})
+	for _, f := range updates {
+		span.SetField(f.Key, f.Value)
+	}
+	return true
+}
+
+// Flush implements tracer.Hook.
+func (hook *Hook) Flush() {}
@@ -0,0 +1,58 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/redact"
+	loggerimpl "github.com/facebookincubator/go-belt/tool/experimental/tracer/implementation/logger"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyEmitter struct {
+	fields field.Fields
+}
+
+func (e *dummyEmitter) Flush() {}
+func (e *dummyEmitter) Emit(entry *types.Entry) {
+	e.fields = field.Gather(entry.Fields)
+}
+
+func TestHook(t *testing.T) {
+	var emitter dummyEmitter
+	l := adapter.LoggerFromEmitter(&emitter).WithLevel(types.LevelTrace)
+	tracer := loggerimpl.New(l, func(span *loggerimpl.SpanImpl) types.Level {
+		return types.LevelTrace
+	}).WithHooks(New(redact.New(redact.OptionRules{
+		{Key: redact.RuleSecretKeys.Key, Strategy: redact.StrategyDrop},
+		redact.RuleEmails,
+	})))
+
+	span := tracer.Start("login of john.doe@example.com", nil)
+	span.SetField("token", "abc")
+	span.SetField("token", "def")
+	span.SetField("attempt", 1)
+	span.Finish()
+
+	values := map[field.Key][]field.Value{}
+	for _, f := range emitter.fields {
+		values[f.Key] = append(values[f.Key], f.Value)
+	}
+	require.Equal(t, []field.Value{"<redacted>"}, values["token"])
+	require.Equal(t, []field.Value{1}, values["attempt"])
+	require.Equal(t, []field.Value{"login of <redacted>"}, values[loggerimpl.FieldNameName])
+}
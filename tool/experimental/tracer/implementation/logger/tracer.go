@@ -110,7 +110,11 @@ func (t *TracerImpl) Flush(ctx context.Context) {
 }
 
 func (t *TracerImpl) send(span *SpanImpl) {
-	fields := span.FieldsValue.
+	if !t.Hooks.ProcessSpan(span) {
+		return
+	}
+
+	fields := latestFields(span.FieldsValue).
 		WithField(FieldNameName, span.NameValue, FieldPropertyName).
 		WithField(FieldNameDuration, span.Duration, FieldPropertyDuration).
 		WithField(FieldNameStartTS, span.StartTSValue, FieldPropertyStartTS)
@@ -134,6 +138,39 @@ func (t *TracerImpl) send(span *SpanImpl) {
 	t.Logger.LogFields(t.LevelFunc(span), "timespan finished", fields)
 }
 
+// latestFields returns the fields with only the latest value of each key
+// (a field could be overwritten using Span.SetField, for example by a Hook).
+func latestFields(fields *field.FieldsChain) *field.FieldsChain {
+	var keysBuf [16]field.Key
+	keys := keysBuf[:0]
+	hasDuplicates := false
+	fields.ForEachField(func(f *field.Field) bool {
+		for _, key := range keys {
+			if key == f.Key {
+				hasDuplicates = true
+				return false
+			}
+		}
+		keys = append(keys, f.Key)
+		return true
+	})
+	if !hasDuplicates {
+		return fields
+	}
+
+	result := make(field.Fields, 0, fields.Len())
+	seen := make(map[field.Key]struct{}, len(keys))
+	fields.ForEachField(func(f *field.Field) bool {
+		if _, ok := seen[f.Key]; ok {
+			return true
+		}
+		seen[f.Key] = struct{}{}
+		result = append(result, *f)
+		return true
+	})
+	return (*field.FieldsChain)(nil).WithFields(result)
+}
+
 type fieldPropertyEnum uint
 
 const (
@@ -31,6 +31,7 @@ import (
 type GenericLogger struct {
 	Emitters        types.Emitters
 	CurrentLevel    types.Level
+	LevelVar        *types.LevelVar
 	Fields          *field.FieldsChain
 	TraceIDs        belt.TraceIDs
 	CurrentPreHooks types.PreHooks
@@ -43,7 +44,12 @@ type GenericLogger struct {
 var _ CompactLogger = (*GenericLogger)(nil)
 
 // Level implements types.CompactLogger.
+//
+// If LevelVar is set, then it has a priority over CurrentLevel.
 func (l *GenericLogger) Level() types.Level {
+	if l.LevelVar != nil {
+		return l.LevelVar.Level()
+	}
 	return l.CurrentLevel
 }
 
@@ -58,6 +64,7 @@ func (l *GenericLogger) WithTraceIDs(allTraceIDs belt.TraceIDs, newTraceIDs int)
 func (l *GenericLogger) WithLevel(newLevel types.Level) CompactLogger {
 	newLogger := *l
 	newLogger.CurrentLevel = newLevel
+	newLogger.LevelVar = nil
 	return &newLogger
 }
 
@@ -118,7 +125,7 @@ func (l *GenericLogger) Emitter() types.Emitter {
 
 // Log implements types.CompactLogger.
 func (l *GenericLogger) Log(level types.Level, values ...any) {
-	preHooksResult := LogPreprocess(l.CurrentPreHooks, l.TraceIDs, level, l.CurrentLevel >= level, values...)
+	preHooksResult := LogPreprocess(l.CurrentPreHooks, l.TraceIDs, level, l.Level() >= level, values...)
 	if preHooksResult.Skip {
 		return
 	}
@@ -158,16 +165,16 @@ func (l *GenericLogger) Log(level types.Level, values ...any) {
 
 // LogFields implements types.CompactLogger.
 func (l *GenericLogger) LogFields(level types.Level, message string, fields field.AbstractFields) {
-	preHooksResult := LogFieldsPreprocess(l.CurrentPreHooks, l.TraceIDs, level, l.CurrentLevel >= level, message, fields)
+	preHooksResult := LogFieldsPreprocess(l.CurrentPreHooks, l.TraceIDs, level, l.Level() >= level, message, fields)
 	if preHooksResult.Skip {
 		return
 	}
 
 	var finalFields field.AbstractFields
 	if preHooksResult.ExtraFields != nil {
-		finalFields = field.Slice[field.AbstractFields]{fields, preHooksResult.ExtraFields}
+		finalFields = field.Slice[field.AbstractFields]{field.ExpandErrors(fields), preHooksResult.ExtraFields}
 	} else {
-		finalFields = fields
+		finalFields = field.ExpandErrors(fields)
 	}
 
 	entry := l.acquireEntry(level, message, finalFields, preHooksResult.ExtraEntryProperties)
@@ -303,7 +310,7 @@ func LogfPreprocess(
 
 // Logf implements types.CompactLogger.
 func (l *GenericLogger) Logf(level types.Level, format string, args ...any) {
-	preHooksResult := LogfPreprocess(l.CurrentPreHooks, l.TraceIDs, level, l.CurrentLevel >= level, format, args...)
+	preHooksResult := LogfPreprocess(l.CurrentPreHooks, l.TraceIDs, level, l.Level() >= level, format, args...)
 	if preHooksResult.Skip {
 		return
 	}
@@ -327,7 +334,7 @@ func (l *GenericLogger) WithEntryProperties(props ...types.EntryProperty) Compac
 		return l
 	}
 	newLogger := *l
-	newLogger.EntryProperties = newLogger.EntryProperties.Add(props)
+	newLogger.EntryProperties = newLogger.EntryProperties.Add(props...)
 	return &newLogger
 }
 
@@ -373,6 +380,7 @@ func (l *GenericLogger) emit(entry *types.Entry) {
 // Flush implements types.CompactLogger.
 func (l *GenericLogger) Flush(context.Context) {
 	l.Emitters.Flush()
+	l.CurrentHooks.Flush()
 }
 
 func (l *GenericLogger) acquireEntry(level types.Level, message string, fields field.AbstractFields, props types.EntryProperties) *types.Entry {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/facebookincubator/go-belt"
 	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/messagetemplate"
 	"github.com/facebookincubator/go-belt/tool/logger/types"
 )
 
@@ -50,6 +51,16 @@ func (l GenericSugar) Logf(level types.Level, format string, args ...any) {
 	l.CompactLogger.Logf(level, format, args...)
 }
 
+// Logt implements logger.Logger.
+func (l GenericSugar) Logt(level types.Level, template string, args ...any) {
+	if level != types.LevelPanic && level != types.LevelFatal && l.CompactLogger.Level() < level {
+		// avoiding rendering the message if it will not be logged anyway
+		return
+	}
+	message, fields := messagetemplate.Parse(template).Execute(args...)
+	l.CompactLogger.LogFields(level, message, fields)
+}
+
 // Flush implements logger.Logger.
 func (l GenericSugar) Flush(ctx context.Context) {
 	l.CompactLogger.Flush(ctx)
@@ -213,3 +224,38 @@ func (l GenericSugar) Panicf(format string, args ...any) {
 func (l GenericSugar) Fatalf(format string, args ...any) {
 	l.Logf(types.LevelFatal, format, args...)
 }
+
+// Tracet implements logger.Logger.
+func (l GenericSugar) Tracet(template string, args ...any) {
+	l.Logt(types.LevelTrace, template, args...)
+}
+
+// Debugt implements logger.Logger.
+func (l GenericSugar) Debugt(template string, args ...any) {
+	l.Logt(types.LevelDebug, template, args...)
+}
+
+// Infot implements logger.Logger.
+func (l GenericSugar) Infot(template string, args ...any) {
+	l.Logt(types.LevelInfo, template, args...)
+}
+
+// Warnt implements logger.Logger.
+func (l GenericSugar) Warnt(template string, args ...any) {
+	l.Logt(types.LevelWarning, template, args...)
+}
+
+// Errort implements logger.Logger.
+func (l GenericSugar) Errort(template string, args ...any) {
+	l.Logt(types.LevelError, template, args...)
+}
+
+// Panict implements logger.Logger.
+func (l GenericSugar) Panict(template string, args ...any) {
+	l.Logt(types.LevelPanic, template, args...)
+}
+
+// Fatalt implements logger.Logger.
+func (l GenericSugar) Fatalt(template string, args ...any) {
+	l.Logt(types.LevelFatal, template, args...)
+}
@@ -51,6 +51,7 @@ func LoggerFromEmitter(emitter types.Emitter, opts ...types.Option) types.Logger
 	return LoggerFromCompactLogger(
 		&GenericLogger{
 			Emitters:      types.Emitters{emitter},
+			LevelVar:      cfg.LevelVar,
 			GetCallerFunc: cfg.GetCallerFunc,
 		},
 		opts...,
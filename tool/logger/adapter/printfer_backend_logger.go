@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/hooks/sanitizer"
 	"github.com/facebookincubator/go-belt/tool/logger/types"
 )
 
@@ -97,7 +98,8 @@ func unstructuredMessageFromEntry(entry *types.Entry) string {
 		switch v := f.Value.(type) {
 		case error:
 			result.WriteString(" error:")
-			result.WriteString(v.Error())
+			// an error message may contain a newline, forging another log line:
+			result.WriteString(sanitizer.Escape(v.Error()))
 		}
 		return true
 	})
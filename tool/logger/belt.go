@@ -21,17 +21,51 @@ import (
 // when no Logger is set. It is used by functions FromBelt and FromCtx.
 var Default = stdlib.Default
 
+type levelArtifactID struct{}
+
+// LevelArtifactID is the unique ArtifactID which defines the logging level
+// override (see BeltWithLevel).
+var LevelArtifactID = levelArtifactID{}
+
+var _ belt.ArtifactID = LevelArtifactID
+
 // FromBelt returns the Logger defined in the Belt. If one is not defined,
 // then the default Logger is returned (see function "Default").
+//
+// If the logging level is overridden in the Belt (see BeltWithLevel)
+// then the returned Logger has the overridden level.
 func FromBelt(belt *belt.Belt) Logger {
+	var logger Logger
 	loggerIface := belt.Tools().GetByID(ToolID)
 	if loggerIface == nil {
-		return Default()
+		logger = Default()
+	} else {
+		logger = loggerIface.(Logger)
+	}
+
+	level, ok := belt.Artifacts().GetByID(LevelArtifactID).(Level)
+	if !ok || logger.Level() == level {
+		return logger
 	}
-	return loggerIface.(Logger)
+	return logger.WithLevel(level)
 }
 
 // BeltWithLogger returns an Belt with the given Logger set.
 func BeltWithLogger(belt *belt.Belt, logger Logger) *belt.Belt {
 	return belt.WithTool(ToolID, logger)
 }
+
+// BeltWithLevel returns an Belt with the logging level overridden for any
+// Logger acquired from it (or its derivatives) through FromBelt (or FromCtx).
+//
+// It allows to change the logging level only for a specific scope, for
+// example to enable Trace logging only for a single request, without
+// changing the logging level globally.
+//
+// Special case: to remove the override use LevelUndefined.
+func BeltWithLevel(belt *belt.Belt, level Level) *belt.Belt {
+	if level == LevelUndefined {
+		return belt.WithArtifact(LevelArtifactID, nil)
+	}
+	return belt.WithArtifact(LevelArtifactID, level)
+}
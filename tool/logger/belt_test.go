@@ -0,0 +1,53 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/stdlib"
+)
+
+func TestCtxWithLevel(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := CtxWithLogger(context.Background(), stdlib.New(log.New(&buf, "", 0), LevelInfo))
+
+	debugCtx := CtxWithLevel(ctx, LevelTrace)
+	debugCtx = belt.WithField(debugCtx, "request_id", 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		Debugf(debugCtx, "inside")
+	}()
+	wg.Wait()
+	Debugf(ctx, "outside")
+	Debugf(CtxWithLevel(debugCtx, LevelUndefined), "reset")
+
+	out := buf.String()
+	if !strings.Contains(out, "inside") {
+		t.Fatalf("the level override was not applied: '%s'", out)
+	}
+	if strings.Contains(out, "outside") || strings.Contains(out, "reset") {
+		t.Fatalf("the level override leaked outside of the context: '%s'", out)
+	}
+	if GetLevel(debugCtx) != LevelTrace || GetLevel(ctx) != LevelInfo {
+		t.Fatalf("unexpected levels: %v %v", GetLevel(debugCtx), GetLevel(ctx))
+	}
+}
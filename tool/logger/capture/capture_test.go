@@ -0,0 +1,140 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package capture
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/klog/v2"
+)
+
+type recordingEmitter struct {
+	entries []types.Entry
+	fields  []map[string]any
+}
+
+func (e *recordingEmitter) Flush() {}
+
+func (e *recordingEmitter) Emit(entry *types.Entry) {
+	fields := map[string]any{}
+	if entry.Fields != nil {
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			fields[f.Key] = f.Value
+			return true
+		})
+	}
+	e.entries = append(e.entries, *entry)
+	e.fields = append(e.fields, fields)
+}
+
+func newRecordingLogger() (types.Logger, *recordingEmitter) {
+	emitter := &recordingEmitter{}
+	return adapter.LoggerFromEmitter(emitter).WithLevel(types.LevelTrace), emitter
+}
+
+func TestParseLevelPrefix(t *testing.T) {
+	for line, expected := range map[string]struct {
+		Level   types.Level
+		Message string
+		OK      bool
+	}{
+		"[WARN] disk is almost full": {types.LevelWarning, "disk is almost full", true},
+		"[D]details":                 {types.LevelDebug, "details", true},
+		"error: unable to open":      {types.LevelError, "unable to open", true},
+		"INFO started port=8080":     {types.LevelInfo, "started port=8080", true},
+		"panic: recovered":           {types.LevelError, "recovered", true},
+		"info about the request":     {types.LevelUndefined, "info about the request", false},
+		"http: TLS handshake error":  {types.LevelUndefined, "http: TLS handshake error", false},
+		"[core] channel created":     {types.LevelUndefined, "[core] channel created", false},
+		"none: something":            {types.LevelUndefined, "none: something", false},
+		"":                           {types.LevelUndefined, "", false},
+	} {
+		level, msg, ok := ParseLevelPrefix(line)
+		require.Equal(t, expected.Level, level, line)
+		require.Equal(t, expected.Message, msg, line)
+		require.Equal(t, expected.OK, ok, line)
+	}
+}
+
+func TestParseKlogLine(t *testing.T) {
+	level, msg, ok := ParseKlogLine("W1019 12:34:56.789012   12345 main.go:42] disk is almost full")
+	require.True(t, ok)
+	require.Equal(t, types.LevelWarning, level)
+	require.Equal(t, "disk is almost full", msg)
+
+	level, _, ok = ParseKlogLine("F1019 12:34:56.789012   12345 main.go:42] unable to start")
+	require.True(t, ok)
+	require.Equal(t, types.LevelError, level)
+
+	_, _, ok = ParseKlogLine("Where is the header?")
+	require.False(t, ok)
+}
+
+func TestInstallStdlog(t *testing.T) {
+	l, emitter := newRecordingLogger()
+	restore := InstallStdlog(l)
+	log.Print("WARNING: disk is almost full")
+	log.Printf("user %d logged in", 123)
+	restore()
+
+	require.Len(t, emitter.entries, 2)
+	require.Equal(t, types.LevelWarning, emitter.entries[0].Level)
+	require.Equal(t, "disk is almost full", emitter.entries[0].Message)
+	require.Equal(t, types.LevelInfo, emitter.entries[1].Level)
+	require.Equal(t, "user 123 logged in", emitter.entries[1].Message)
+
+	// This test is a part of go-belt, so it is skipped as well; the point is
+	// to skip package "log".
+	file, _ := emitter.entries[0].Caller.FileLine()
+	require.NotEqual(t, "log", filepath.Base(filepath.Dir(file)))
+}
+
+func TestInstallKlog(t *testing.T) {
+	l, emitter := newRecordingLogger()
+	restore := InstallKlog(l)
+	klog.Warning("disk is almost full")
+	klog.InfoS("pod is ready", "pod", "nginx")
+	klog.ErrorS(errors.New("unable to pull"), "pod failed", "pod", "nginx")
+	restore()
+
+	require.Len(t, emitter.entries, 3)
+	require.Equal(t, types.LevelWarning, emitter.entries[0].Level)
+	require.Equal(t, "disk is almost full", emitter.entries[0].Message)
+	require.Equal(t, types.LevelInfo, emitter.entries[1].Level)
+	require.Equal(t, "pod is ready", emitter.entries[1].Message)
+	require.Equal(t, "nginx", emitter.fields[1]["pod"])
+	require.Equal(t, types.LevelError, emitter.entries[2].Level)
+	require.Equal(t, "nginx", emitter.fields[2]["pod"])
+}
+
+func TestGRPCLogger(t *testing.T) {
+	l, emitter := newRecordingLogger()
+	grpcLogger := NewGRPCLogger(l.WithLevel(types.LevelDebug))
+	grpcLogger.Warningf("retrying in %ds", 5)
+	grpcLogger.Infoln("channel", "created")
+	require.True(t, grpcLogger.V(1))
+	require.False(t, grpcLogger.V(2))
+
+	require.Len(t, emitter.entries, 2)
+	require.Equal(t, types.LevelWarning, emitter.entries[0].Level)
+	require.Equal(t, "retrying in 5s", emitter.entries[0].Message)
+	require.Equal(t, types.LevelInfo, emitter.entries[1].Level)
+	require.Equal(t, "channel created", emitter.entries[1].Message)
+}
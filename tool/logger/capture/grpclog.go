@@ -0,0 +1,120 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package capture
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+	beltlogr "github.com/facebookincubator/go-belt/tool/logger/implementation/logr"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"google.golang.org/grpc/grpclog"
+)
+
+// GRPCLogger is an implementation of grpclog.LoggerV2 based on a Logger.
+type GRPCLogger struct {
+	Logger types.Logger
+}
+
+var _ grpclog.LoggerV2 = (*GRPCLogger)(nil)
+
+// NewGRPCLogger returns a new instance of GRPCLogger.
+func NewGRPCLogger(logger types.Logger) *GRPCLogger {
+	return &GRPCLogger{
+		Logger: logger,
+	}
+}
+
+// InstallGRPCLog redirects grpclog to the given Logger.
+//
+// Similar to grpclog.SetLoggerV2, it should be called before any gRPC functions.
+func InstallGRPCLog(logger types.Logger) {
+	runtime.SkipCallerPackages("google.golang.org/grpc/grpclog", "google.golang.org/grpc/internal/grpclog")
+	grpclog.SetLoggerV2(NewGRPCLogger(logger))
+}
+
+func (l *GRPCLogger) log(level types.Level, args ...any) {
+	l.Logger.Log(level, fmt.Sprint(args...))
+}
+
+func (l *GRPCLogger) logln(level types.Level, args ...any) {
+	l.Logger.Log(level, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Info implements grpclog.LoggerV2.
+func (l *GRPCLogger) Info(args ...any) {
+	l.log(types.LevelInfo, args...)
+}
+
+// Infoln implements grpclog.LoggerV2.
+func (l *GRPCLogger) Infoln(args ...any) {
+	l.logln(types.LevelInfo, args...)
+}
+
+// Infof implements grpclog.LoggerV2.
+func (l *GRPCLogger) Infof(format string, args ...any) {
+	l.Logger.Logf(types.LevelInfo, format, args...)
+}
+
+// Warning implements grpclog.LoggerV2.
+func (l *GRPCLogger) Warning(args ...any) {
+	l.log(types.LevelWarning, args...)
+}
+
+// Warningln implements grpclog.LoggerV2.
+func (l *GRPCLogger) Warningln(args ...any) {
+	l.logln(types.LevelWarning, args...)
+}
+
+// Warningf implements grpclog.LoggerV2.
+func (l *GRPCLogger) Warningf(format string, args ...any) {
+	l.Logger.Logf(types.LevelWarning, format, args...)
+}
+
+// Error implements grpclog.LoggerV2.
+func (l *GRPCLogger) Error(args ...any) {
+	l.log(types.LevelError, args...)
+}
+
+// Errorln implements grpclog.LoggerV2.
+func (l *GRPCLogger) Errorln(args ...any) {
+	l.logln(types.LevelError, args...)
+}
+
+// Errorf implements grpclog.LoggerV2.
+func (l *GRPCLogger) Errorf(format string, args ...any) {
+	l.Logger.Logf(types.LevelError, format, args...)
+}
+
+// Fatal implements grpclog.LoggerV2.
+func (l *GRPCLogger) Fatal(args ...any) {
+	l.log(types.LevelFatal, args...)
+}
+
+// Fatalln implements grpclog.LoggerV2.
+func (l *GRPCLogger) Fatalln(args ...any) {
+	l.logln(types.LevelFatal, args...)
+}
+
+// Fatalf implements grpclog.LoggerV2.
+func (l *GRPCLogger) Fatalf(format string, args ...any) {
+	l.Logger.Logf(types.LevelFatal, format, args...)
+}
+
+// V implements grpclog.LoggerV2.
+//
+// The verbosity is mapped to a level the same way as logr's one (see logr.LevelFromVerbosity).
+func (l *GRPCLogger) V(verbosity int) bool {
+	return l.Logger.Level() >= beltlogr.LevelFromVerbosity(verbosity)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package capture
+
+import (
+	"strings"
+
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+	beltlogr "github.com/facebookincubator/go-belt/tool/logger/implementation/logr"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"k8s.io/klog/v2"
+)
+
+// InstallKlog redirects klog to the given Logger.
+//
+// Structured messages (like klog.InfoS) are logged with their keys and values
+// as fields, through a logr.LogSink (see logr.NewLogr). Unstructured messages are
+// logged with the level taken from the klog's header (see ParseKlogLine).
+//
+// It returns a function which restores the default behavior of klog.
+func InstallKlog(logger types.Logger, opts ...Option) (restore func()) {
+	runtime.SkipCallerPackages("k8s.io/klog")
+
+	writer := NewWriter(logger, opts...)
+	writer.ParseLine = ParseKlogLine
+	klog.SetLoggerWithOptions(
+		beltlogr.NewLogr(logger),
+		klog.WriteKlogBuffer(func(b []byte) {
+			_, _ = writer.Write(b)
+		}),
+	)
+	return klog.ClearLogger
+}
+
+// ParseKlogLine extracts the level from a line formatted by klog, like:
+//
+//	W1019 12:34:56.789012   12345 main.go:42] disk is almost full
+//
+// The message is returned without the header. Fatal lines are considered
+// as errors (same as in ParseLevelPrefix): klog exits by itself.
+func ParseKlogLine(line string) (types.Level, string, bool) {
+	if len(line) < 5 {
+		return types.LevelUndefined, line, false
+	}
+	var level types.Level
+	switch line[0] {
+	case 'I':
+		level = types.LevelInfo
+	case 'W':
+		level = types.LevelWarning
+	case 'E', 'F':
+		level = types.LevelError
+	default:
+		return types.LevelUndefined, line, false
+	}
+	for _, c := range line[1:5] {
+		if c < '0' || c > '9' {
+			return types.LevelUndefined, line, false
+		}
+	}
+	end := strings.Index(line, "] ")
+	if end < 0 {
+		return types.LevelUndefined, line, false
+	}
+	return level, line[end+2:], true
+}
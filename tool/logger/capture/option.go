@@ -0,0 +1,60 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package capture
+
+import (
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Option is an optional argument to functions NewWriter, InstallStdlog and InstallKlog.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		DefaultLevel: types.LevelInfo,
+		ParseLine:    ParseLevelPrefix,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	DefaultLevel types.Level
+	ParseLine    ParseLineFunc
+}
+
+// OptionDefaultLevel defines the level of messages without
+// a recognized level prefix.
+//
+// The default value is types.LevelInfo.
+type OptionDefaultLevel types.Level
+
+func (opt OptionDefaultLevel) apply(cfg *config) {
+	cfg.DefaultLevel = types.Level(opt)
+}
+
+// OptionParseLine overrides the function used to extract the level
+// from a line written to Writer.
+//
+// The default value is ParseLevelPrefix. It is ignored by InstallKlog.
+type OptionParseLine ParseLineFunc
+
+func (opt OptionParseLine) apply(cfg *config) {
+	cfg.ParseLine = ParseLineFunc(opt)
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package capture redirects logs of third-party logging libraries (the standard
+// package "log", klog and grpclog) to a Logger, so that they are processed
+// by the same hooks and Emitters as the rest of the application.
+//
+// Messages of these libraries are usually unstructured, so the level is
+// parsed from the prefix of a message where possible (see ParseLevelPrefix).
+// The caller is detected by the Logger, skipping the functions of the library
+// (see runtime.SkipCallerPackages).
+package capture
+
+import (
+	"strings"
+
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// maxLevelPrefixLength is the maximal length of a level name
+// which ParseLevelPrefix tries to parse.
+const maxLevelPrefixLength = 16
+
+// ParseLineFunc extracts the level from a line. It returns
+// the level, the message without the level prefix and true; or
+// false if the level was not found.
+type ParseLineFunc func(line string) (types.Level, string, bool)
+
+// Writer is an io.Writer which logs each written message through a Logger.
+//
+// Each call of Write is logged as a separate entry (the standard package
+// "log" and klog write each message by a single call).
+type Writer struct {
+	Logger       types.Logger
+	DefaultLevel types.Level
+	ParseLine    ParseLineFunc
+}
+
+// NewWriter returns a new instance of Writer.
+func NewWriter(logger types.Logger, opts ...Option) *Writer {
+	cfg := options(opts).Config()
+	return &Writer{
+		Logger:       logger,
+		DefaultLevel: cfg.DefaultLevel,
+		ParseLine:    cfg.ParseLine,
+	}
+}
+
+// Write implements io.Writer.
+func (w *Writer) Write(b []byte) (int, error) {
+	line := strings.TrimRight(string(b), "\r\n")
+	level, msg, ok := types.LevelUndefined, line, false
+	if w.ParseLine != nil {
+		level, msg, ok = w.ParseLine(line)
+	}
+	if !ok {
+		level, msg = w.DefaultLevel, line
+	}
+	w.Logger.Log(level, msg)
+	return len(b), nil
+}
+
+// ParseLevelPrefix extracts the level from the most common styles of
+// level prefixes:
+//
+//	[WARN] disk is almost full
+//	error: unable to open the file
+//	INFO started (this is how log/slog writes through the package "log")
+//
+// The level names are parsed by types.ParseLogLevel (so custom levels
+// are supported as well). Panic and fatal prefixes are considered as errors.
+func ParseLevelPrefix(line string) (types.Level, string, bool) {
+	var name, msg string
+	switch {
+	case strings.HasPrefix(line, "["):
+		end := strings.IndexByte(line, ']')
+		if end < 0 {
+			return types.LevelUndefined, line, false
+		}
+		name, msg = line[1:end], line[end+1:]
+	default:
+		end := strings.IndexAny(line, ": ")
+		if end < 2 {
+			return types.LevelUndefined, line, false
+		}
+		name, msg = line[:end], line[end+1:]
+		if line[end] == ' ' && strings.ToUpper(name) != name {
+			// only "INFO message" is considered, but not "info message"
+			return types.LevelUndefined, line, false
+		}
+	}
+	if len(name) > maxLevelPrefixLength {
+		return types.LevelUndefined, line, false
+	}
+	level, err := types.ParseLogLevel(name)
+	if err != nil || level == types.LevelNone {
+		return types.LevelUndefined, line, false
+	}
+	if level <= types.LevelPanic {
+		// A message which just looks like a panic should not crash
+		// the application (and if it is a real panic or fatal, then
+		// the library panics or exits by itself).
+		level = types.LevelError
+	}
+	return level, strings.TrimLeft(msg, " "), true
+}
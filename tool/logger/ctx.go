@@ -30,6 +30,22 @@ func CtxWithLogger(ctx context.Context, logger Logger) context.Context {
 	return belt.WithTool(ctx, ToolID, logger)
 }
 
+// CtxWithLevel returns a context with the logging level overridden for any
+// Logger acquired through FromCtx from this context (or its derivatives, including
+// the ones passed to other goroutines).
+//
+// It allows to change the logging level only for a specific scope, for
+// example to enable Trace logging only for a single request:
+//
+//	if req.Header.Get("X-Debug") != "" {
+//		ctx = logger.CtxWithLevel(ctx, logger.LevelTrace)
+//	}
+//
+// Special case: to remove the override use LevelUndefined.
+func CtxWithLevel(ctx context.Context, level Level) context.Context {
+	return belt.CtxWithBelt(ctx, BeltWithLevel(belt.CtxBelt(ctx), level))
+}
+
 // Flush forces to flush all buffers.
 func Flush(ctx context.Context) {
 	FromCtx(ctx).Flush(ctx)
@@ -130,6 +146,22 @@ func Log(ctx context.Context, level Level, values ...any) {
 	FromCtx(ctx).Log(level, values...)
 }
 
+// Logt logs a message defined by a template with named placeholders,
+// like "user {user_id} logged in from {ip}".
+//
+// The arguments are assigned to the placeholders positionally and
+// are both rendered into the message and logged as structured fields.
+// The template itself is logged as field "message_template", so
+// it could be used to group messages regardless of the values.
+// See messagetemplate.Template.Execute for details.
+//
+// Examples:
+//
+//	logger.Logt(ctx, logger.LevelInfo, "user {user_id} logged in from {ip}", user.ID, remoteAddr)
+func Logt(ctx context.Context, level Level, template string, args ...any) {
+	FromCtx(ctx).Logt(level, template, args...)
+}
+
 // GetEmitter returns the Emitter (see the description of interface "Emitter").
 func GetEmitter(ctx context.Context) Emitter {
 	return FromCtx(ctx).Emitter()
@@ -257,6 +289,45 @@ func Fatalf(ctx context.Context, format string, args ...any) {
 	FromCtx(ctx).Fatalf(format, args...)
 }
 
+// Tracet is just a shorthand for Logt(ctx, logger.LevelTrace, ...)
+func Tracet(ctx context.Context, template string, args ...any) {
+	FromCtx(ctx).Tracet(template, args...)
+}
+
+// Debugt is just a shorthand for Logt(ctx, logger.LevelDebug, ...)
+func Debugt(ctx context.Context, template string, args ...any) {
+	FromCtx(ctx).Debugt(template, args...)
+}
+
+// Infot is just a shorthand for Logt(ctx, logger.LevelInfo, ...)
+func Infot(ctx context.Context, template string, args ...any) {
+	FromCtx(ctx).Infot(template, args...)
+}
+
+// Warnt is just a shorthand for Logt(ctx, logger.LevelWarn, ...)
+func Warnt(ctx context.Context, template string, args ...any) {
+	FromCtx(ctx).Warnt(template, args...)
+}
+
+// Errort is just a shorthand for Logt(ctx, logger.LevelError, ...)
+func Errort(ctx context.Context, template string, args ...any) {
+	FromCtx(ctx).Errort(template, args...)
+}
+
+// Panict is just a shorthand for Logt(ctx, logger.LevelPanic, ...)
+//
+// Be aware: Panic level also triggers a `panic`.
+func Panict(ctx context.Context, template string, args ...any) {
+	FromCtx(ctx).Panict(template, args...)
+}
+
+// Fatalt is just a shorthand for Logt(ctx, logger.LevelFatal, ...)
+//
+// Be aware: Fatal level also triggers an `os.Exit`.
+func Fatalt(ctx context.Context, template string, args ...any) {
+	FromCtx(ctx).Fatalt(template, args...)
+}
+
 // WithLevel returns a logger with logger level set to the given argument.
 //
 // See also the description of type "Level".
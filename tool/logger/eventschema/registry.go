@@ -0,0 +1,135 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package eventschema is a registry of schemas of structured log events,
+// which allows to introspect the events in runtime (for example to document,
+// validate or route them). The schemas are usually registered by the code
+// generated by command eventsgen
+// (see github.com/facebookincubator/go-belt/cmd/eventsgen).
+package eventschema
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// EventKey is the key of the field, which contains the name of the event.
+const EventKey = "event"
+
+// Field is a schema of a field of an event.
+type Field struct {
+	// Key is the key of the field.
+	Key field.Key
+
+	// Type is the Go type of the value.
+	Type string
+
+	// Doc is the documentation of the field.
+	Doc string
+}
+
+// Event is a schema of a structured log event.
+type Event struct {
+	// Name is the unique name of the event (see EventKey).
+	Name string
+
+	// Level is the logging level of the event.
+	Level types.Level
+
+	// Message is the message of the event.
+	Message string
+
+	// Doc is the documentation of the event.
+	Doc string
+
+	// Fields are the fields of the event (in addition to EventKey).
+	Fields []Field
+}
+
+var (
+	locker sync.RWMutex
+	events = map[string]Event{}
+)
+
+// Register adds schemas of events to the registry. It returns an error
+// if an event with the same name is already registered (then none of the
+// events are registered).
+func Register(newEvents ...Event) error {
+	locker.Lock()
+	defer locker.Unlock()
+	seen := make(map[string]struct{}, len(newEvents))
+	for _, ev := range newEvents {
+		if _, ok := events[ev.Name]; ok {
+			return fmt.Errorf("event '%s' is already registered", ev.Name)
+		}
+		if _, ok := seen[ev.Name]; ok {
+			return fmt.Errorf("event '%s' is defined twice", ev.Name)
+		}
+		seen[ev.Name] = struct{}{}
+	}
+	for _, ev := range newEvents {
+		events[ev.Name] = ev
+	}
+	return nil
+}
+
+// MustRegister is the same as Register, but panics on an error.
+func MustRegister(newEvents ...Event) {
+	if err := Register(newEvents...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the schema of the event with the given name.
+func Lookup(name string) (Event, bool) {
+	locker.RLock()
+	defer locker.RUnlock()
+	ev, ok := events[name]
+	return ev, ok
+}
+
+// FromEntry returns the schema of the event logged as the given entry
+// (by the value of the field with key EventKey).
+func FromEntry(entry *types.Entry) (Event, bool) {
+	var name string
+	if entry.Fields != nil {
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			if f.Key != EventKey {
+				return true
+			}
+			name, _ = f.Value.(string)
+			return false
+		})
+	}
+	if name == "" {
+		return Event{}, false
+	}
+	return Lookup(name)
+}
+
+// All returns the schemas of all the registered events sorted by name.
+func All() []Event {
+	locker.RLock()
+	defer locker.RUnlock()
+	result := make([]Event, 0, len(events))
+	for _, ev := range events {
+		result = append(result, ev)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
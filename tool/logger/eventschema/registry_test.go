@@ -0,0 +1,49 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package eventschema
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	require.NoError(t, Register(
+		Event{Name: "TestB", Level: types.LevelInfo, Message: "b"},
+		Event{Name: "TestA", Level: types.LevelDebug, Message: "a", Fields: []Field{{Key: "id", Type: "int"}}},
+	))
+	require.Error(t, Register(Event{Name: "TestC"}, Event{Name: "TestC"}))
+	require.Error(t, Register(Event{Name: "TestC"}, Event{Name: "TestA"}))
+	_, ok := Lookup("TestC")
+	require.False(t, ok)
+	require.Panics(t, func() { MustRegister(Event{Name: "TestB"}) })
+
+	ev, ok := Lookup("TestA")
+	require.True(t, ok)
+	require.Equal(t, "a", ev.Message)
+
+	var names []string
+	for _, ev := range All() {
+		names = append(names, ev.Name)
+	}
+	require.Equal(t, []string{"TestA", "TestB"}, names)
+
+	ev, ok = FromEntry(&types.Entry{Fields: field.Fields{{Key: EventKey, Value: "TestB"}}})
+	require.True(t, ok)
+	require.Equal(t, "TestB", ev.Name)
+	_, ok = FromEntry(&types.Entry{})
+	require.False(t, ok)
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package counter provides a logger Hook, which derives counter metrics from log entries.
+package counter
+
+import (
+	"fmt"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/experimental/metrics"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+var (
+	// DefaultEntriesMetricKey is the default key of the built-in counter
+	// of entries per level (see LevelLabel).
+	DefaultEntriesMetricKey = "log_entries"
+
+	// LevelLabel is the label of the built-in counter containing the logging level.
+	LevelLabel field.Key = "level"
+)
+
+// Hook is a types.Hook implementation which increments counters (metrics.Count)
+// on log entries, without touching the places where the entries are logged.
+//
+// There is a built-in counter of entries per level (see OptionEntriesMetricKey),
+// and custom counters could be defined through rules (see OptionRules).
+//
+// Setup example:
+//
+//	import (
+//		"github.com/facebookincubator/go-belt/tool/logger/hooks/counter"
+//	)
+//
+//	func main() {
+//		...
+//		l := logger.FromBelt(belt).WithHooks(counter.NewFromBelt(belt, counter.OptionRules{{
+//			MetricKey: "payment_failures",
+//			Level:     logger.LevelError,
+//			Message:   regexp.MustCompile(`^payment`),
+//			Labels:    []field.Key{"provider"},
+//		}}))
+//		belt = logger.BeltWithLogger(belt, l)
+//		...
+//	}
+type Hook struct {
+	Metrics          metrics.Metrics
+	EntriesMetricKey string
+	Rules            []Rule
+}
+
+var _ types.Hook = (*Hook)(nil)
+
+// New returns a new instance of Hook, which increments counters of the given Metrics.
+func New(m metrics.Metrics, opts ...Option) *Hook {
+	cfg := options(opts).Config()
+	return &Hook{
+		Metrics:          m,
+		EntriesMetricKey: cfg.EntriesMetricKey,
+		Rules:            cfg.Rules,
+	}
+}
+
+// NewFromBelt returns a new instance of Hook, which increments counters
+// of the Metrics of the given Belt.
+func NewFromBelt(belt *belt.Belt, opts ...Option) *Hook {
+	return New(metrics.FromBelt(belt), opts...)
+}
+
+// ProcessLogEntry implements types.Hook.
+func (hook *Hook) ProcessLogEntry(entry *types.Entry) bool {
+	if hook.EntriesMetricKey != "" {
+		hook.Metrics.CountFields(hook.EntriesMetricKey, field.Fields{
+			label(LevelLabel, entry.Level.String()),
+		}).Add(1)
+	}
+
+	var fields map[field.Key]field.Value
+	for idx := range hook.Rules {
+		rule := &hook.Rules[idx]
+		if fields == nil && rule.needsFields() {
+			fields = entryFields(entry)
+		}
+		if !rule.match(entry, fields) {
+			continue
+		}
+		if len(rule.Labels) == 0 {
+			hook.Metrics.Count(rule.MetricKey).Add(1)
+			continue
+		}
+		labels := make(field.Fields, 0, len(rule.Labels))
+		for _, key := range rule.Labels {
+			var value string
+			if v, ok := fields[key]; ok {
+				value = fmt.Sprint(v)
+			}
+			labels = append(labels, label(key, value))
+		}
+		hook.Metrics.CountFields(rule.MetricKey, labels).Add(1)
+	}
+	return true
+}
+
+// Flush implements types.Hook.
+func (*Hook) Flush() {}
+
+func label(key field.Key, value string) field.Field {
+	return field.Field{
+		Key:        key,
+		Value:      value,
+		Properties: field.Properties{metrics.FieldPropInclude},
+	}
+}
+
+func entryFields(entry *types.Entry) map[field.Key]field.Value {
+	result := map[field.Key]field.Value{}
+	if entry.Fields == nil {
+		return result
+	}
+	entry.Fields.ForEachField(func(f *field.Field) bool {
+		result[f.Key] = field.ResolveValue(f.Value)
+		return true
+	})
+	return result
+}
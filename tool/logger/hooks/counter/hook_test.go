@@ -0,0 +1,63 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package counter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/experimental/metrics/implementation/simplemetrics"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	m := simplemetrics.New()
+	hook := New(m, OptionRules{
+		{
+			MetricKey: "errors",
+			Level:     types.LevelError,
+			Labels:    []field.Key{"provider"},
+		},
+		{
+			MetricKey: "timeouts",
+			Message:   regexp.MustCompile(`timeout`),
+			Fields:    []FieldPredicate{FieldEquals("retry", true)},
+		},
+	})
+
+	for _, entry := range []*types.Entry{
+		{Level: types.LevelInfo, Message: "hello"},
+		{Level: types.LevelInfo, Message: "timeout", Fields: field.Fields{{Key: "retry", Value: true}}},
+		{Level: types.LevelWarning, Message: "timeout", Fields: field.Fields{{Key: "retry", Value: false}}},
+		{Level: types.LevelError, Message: "failed", Fields: field.Fields{{Key: "provider", Value: "visa"}}},
+		{Level: types.LevelPanic, Message: "failed", Fields: field.Fields{{Key: "provider", Value: "visa"}}},
+		{Level: types.LevelError, Message: "failed"},
+	} {
+		require.True(t, hook.ProcessLogEntry(entry))
+	}
+
+	require.Equal(t, uint64(2), m.CountFields(DefaultEntriesMetricKey, field.Fields{label(LevelLabel, "info")}).Value())
+	require.Equal(t, uint64(2), m.CountFields(DefaultEntriesMetricKey, field.Fields{label(LevelLabel, "error")}).Value())
+	require.Equal(t, uint64(2), m.CountFields("errors", field.Fields{label("provider", "visa")}).Value())
+	require.Equal(t, uint64(1), m.CountFields("errors", field.Fields{label("provider", "")}).Value())
+	require.Equal(t, uint64(1), m.Count("timeouts").Value())
+}
+
+func TestHookNoEntriesCounter(t *testing.T) {
+	m := simplemetrics.New()
+	hook := New(m, OptionEntriesMetricKey(""))
+	hook.ProcessLogEntry(&types.Entry{Level: types.LevelInfo})
+	require.Equal(t, uint64(0), m.CountFields(DefaultEntriesMetricKey, field.Fields{label(LevelLabel, "info")}).Value())
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package counter
+
+// Option is an optional argument to function New, that changes the behavior of the Hook.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		EntriesMetricKey: DefaultEntriesMetricKey,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	EntriesMetricKey string
+	Rules            []Rule
+}
+
+// OptionEntriesMetricKey overrides the key of the built-in counter
+// of entries per level (see DefaultEntriesMetricKey). An empty
+// string disables the built-in counter.
+type OptionEntriesMetricKey string
+
+func (opt OptionEntriesMetricKey) apply(cfg *config) {
+	cfg.EntriesMetricKey = string(opt)
+}
+
+// OptionRules adds rules of counting entries.
+type OptionRules []Rule
+
+func (opt OptionRules) apply(cfg *config) {
+	cfg.Rules = append(cfg.Rules, opt...)
+}
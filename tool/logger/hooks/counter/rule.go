@@ -0,0 +1,111 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package counter
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Rule defines which entries should increment which metric.
+//
+// An entry matches the rule if it matches all the defined conditions.
+type Rule struct {
+	// MetricKey is the key of the metrics.Count to be incremented.
+	MetricKey string
+
+	// Level (if defined) makes the rule match only entries
+	// of this level or of more severe ones.
+	Level types.Level
+
+	// Message (if defined) makes the rule match only entries
+	// with a message matching the regular expression.
+	Message *regexp.Regexp
+
+	// Fields makes the rule match only entries which satisfy all the predicates.
+	Fields []FieldPredicate
+
+	// Labels are keys of entry fields which values are used as labels
+	// of the metric. An empty value is used if an entry has no such field.
+	//
+	// Beware: each unique combination of values creates a new time series
+	// in the metrics backend, so the values should be of a low cardinality.
+	Labels []field.Key
+}
+
+// FieldPredicate is a condition on a value of an entry field.
+type FieldPredicate struct {
+	// Key is the key of the field.
+	Key field.Key
+
+	// Match returns true if the value satisfies the condition.
+	// It is called only if the entry has the field.
+	Match func(value field.Value) bool
+}
+
+// FieldExists returns a FieldPredicate which is satisfied if the entry has the field.
+func FieldExists(key field.Key) FieldPredicate {
+	return FieldPredicate{
+		Key: key,
+		Match: func(field.Value) bool {
+			return true
+		},
+	}
+}
+
+// FieldEquals returns a FieldPredicate which is satisfied if the value
+// of the field has the same string representation as the given value.
+func FieldEquals(key field.Key, value field.Value) FieldPredicate {
+	expected := fmt.Sprint(value)
+	return FieldPredicate{
+		Key: key,
+		Match: func(value field.Value) bool {
+			return fmt.Sprint(value) == expected
+		},
+	}
+}
+
+// FieldMatches returns a FieldPredicate which is satisfied if the string
+// representation of the value of the field matches the regular expression.
+func FieldMatches(key field.Key, re *regexp.Regexp) FieldPredicate {
+	return FieldPredicate{
+		Key: key,
+		Match: func(value field.Value) bool {
+			return re.MatchString(fmt.Sprint(value))
+		},
+	}
+}
+
+func (rule *Rule) match(entry *types.Entry, fields map[field.Key]field.Value) bool {
+	if !types.MatchLevelAndTime(entry.Level, entry.Timestamp, rule.Level, time.Time{}, time.Time{}) {
+		return false
+	}
+	if rule.Message != nil && !rule.Message.MatchString(entry.Message) {
+		return false
+	}
+	for _, predicate := range rule.Fields {
+		value, ok := fields[predicate.Key]
+		if !ok || !predicate.Match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (rule *Rule) needsFields() bool {
+	return len(rule.Fields) > 0 || len(rule.Labels) > 0
+}
@@ -0,0 +1,203 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package deduplicator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+var (
+	// DefaultRepeatedKey is the default field name to store the amount of
+	// suppressed repeats in the summary entry.
+	DefaultRepeatedKey = "repeated"
+)
+
+// Hook is a types.Hook implementation which suppresses repeated log entries.
+// This is supposed to be used to protect the logging pipeline from flapping
+// dependencies, which may produce thousands of identical entries per second.
+//
+// Entries are considered repeated if they have the same logging level,
+// the same message and the same values of the fields selected by OptionKeyFields.
+// The first entry is passed through and starts a window. All the repeats within
+// the window are suppressed, and when the window closes (or on Flush) a single
+// summary entry (the last suppressed one with an additional field
+// "repeated=N") is sent directly to the Emitter.
+//
+// Entries of levels Panic and Fatal are never suppressed.
+//
+// The fields selected by OptionKeyFields are looked up in Entry.Fields only.
+// The context fields (see Logger.WithField) are found there if the Logger
+// implementation passes them to the hooks (for example the zap and logrus
+// ones do), but the zerolog one compiles them into the backend logger
+// without passing them through Entry.Fields, so with it only the fields
+// of the entry itself may be used as KeyFields.
+//
+// Setup example:
+//
+//	import (
+//		"github.com/facebookincubator/go-belt/tool/logger/hooks/deduplicator"
+//		"github.com/facebookincubator/go-belt/tool/logger/implementation/zap"
+//	)
+//
+//	func main() {
+//		...
+//		l := zap.Default()
+//		l = l.WithHooks(deduplicator.New(l.Emitter(), time.Second))
+//		ctx = logger.CtxWithLogger(ctx, l)
+//		...
+//	}
+type Hook struct {
+	Emitter     types.Emitter
+	Window      time.Duration
+	KeyFields   []field.Key
+	RepeatedKey field.Key
+
+	locker  sync.Mutex
+	windows map[string]*window
+}
+
+var _ types.Hook = (*Hook)(nil)
+
+type window struct {
+	timer    *time.Timer
+	repeated uint64
+	last     types.Entry
+}
+
+// New returns a new instance of Hook, which sends summary entries to
+// the given Emitter.
+func New(emitter types.Emitter, windowDuration time.Duration, opts ...Option) *Hook {
+	cfg := options(opts).Config()
+	return &Hook{
+		Emitter:     emitter,
+		Window:      windowDuration,
+		KeyFields:   cfg.KeyFields,
+		RepeatedKey: cfg.RepeatedKey,
+		windows:     map[string]*window{},
+	}
+}
+
+// ProcessLogEntry implements types.Hook.
+func (hook *Hook) ProcessLogEntry(entry *types.Entry) bool {
+	switch entry.Level {
+	case types.LevelPanic, types.LevelFatal:
+		return true
+	}
+
+	key := hook.entryKey(entry)
+
+	hook.locker.Lock()
+	defer hook.locker.Unlock()
+
+	w := hook.windows[key]
+	if w == nil {
+		w = &window{}
+		w.timer = time.AfterFunc(hook.Window, func() {
+			hook.closeWindow(key, w)
+		})
+		hook.windows[key] = w
+		return true
+	}
+
+	w.repeated++
+	w.last = copyEntry(entry)
+	return false
+}
+
+// Flush implements types.Hook.
+//
+// It closes all the current windows and sends out the summary entries.
+func (hook *Hook) Flush() {
+	hook.locker.Lock()
+	windows := hook.windows
+	hook.windows = map[string]*window{}
+	hook.locker.Unlock()
+
+	for _, w := range windows {
+		w.timer.Stop()
+		hook.emitSummary(w)
+	}
+	hook.Emitter.Flush()
+}
+
+func (hook *Hook) closeWindow(key string, w *window) {
+	hook.locker.Lock()
+	if hook.windows[key] != w {
+		// already closed by Flush
+		hook.locker.Unlock()
+		return
+	}
+	delete(hook.windows, key)
+	hook.locker.Unlock()
+
+	hook.emitSummary(w)
+}
+
+func (hook *Hook) emitSummary(w *window) {
+	if w.repeated == 0 {
+		return
+	}
+
+	entry := w.last
+	entry.Fields = field.Add(entry.Fields, &field.Field{
+		Key:   hook.RepeatedKey,
+		Value: w.repeated,
+	})
+	hook.Emitter.Emit(&entry)
+}
+
+func (hook *Hook) entryKey(entry *types.Entry) string {
+	var key strings.Builder
+	key.WriteByte(entry.Level.Byte())
+	key.WriteString(entry.Message)
+	if len(hook.KeyFields) == 0 || entry.Fields == nil {
+		return key.String()
+	}
+
+	values := make([]any, len(hook.KeyFields))
+	entry.Fields.ForEachField(func(f *field.Field) bool {
+		for idx, keyField := range hook.KeyFields {
+			if f.Key == keyField {
+				values[idx] = f.Value
+			}
+		}
+		return true
+	})
+	for _, value := range values {
+		key.WriteByte(0)
+		fmt.Fprint(&key, value)
+	}
+	return key.String()
+}
+
+// copyEntry copies the entry, because the original one may be
+// reused by the Logger after the hook returned.
+func copyEntry(entry *types.Entry) types.Entry {
+	cpy := types.Entry{
+		Timestamp: entry.Timestamp,
+		Level:     entry.Level,
+		Message:   entry.Message,
+		TraceIDs:  entry.TraceIDs,
+		Caller:    entry.Caller,
+	}
+	if entry.Fields != nil {
+		cpy.Fields = field.Gather(entry.Fields)
+	}
+	return cpy
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package deduplicator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/zap"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+	upstreamzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type dummyEmitter struct {
+	locker  sync.Mutex
+	Entries []types.Entry
+}
+
+func (e *dummyEmitter) Flush() {}
+
+func (e *dummyEmitter) Emit(entry *types.Entry) {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	e.Entries = append(e.Entries, *entry)
+}
+
+func (e *dummyEmitter) Len() int {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	return len(e.Entries)
+}
+
+func TestHook(t *testing.T) {
+	emitter := &dummyEmitter{}
+	l := adapter.LoggerFromEmitter(emitter).WithLevel(types.LevelTrace)
+	l = l.WithHooks(New(emitter, time.Hour, OptionKeyFields{"host"}))
+
+	for i := 0; i < 5; i++ {
+		l.WithField("host", "a").Error("connection refused")
+	}
+	l.WithField("host", "b").Error("connection refused")
+	l.Warn("connection refused")
+	require.Len(t, emitter.Entries, 3)
+
+	l.Flush(context.Background())
+	require.Len(t, emitter.Entries, 4)
+
+	summary := emitter.Entries[3]
+	require.Equal(t, "connection refused", summary.Message)
+	require.Equal(t, types.LevelError, summary.Level)
+	fields := map[field.Key]field.Value{}
+	summary.Fields.ForEachField(func(f *field.Field) bool {
+		fields[f.Key] = f.Value
+		return true
+	})
+	require.Equal(t, "a", fields["host"])
+	require.Equal(t, uint64(4), fields[DefaultRepeatedKey])
+
+	// the window is closed, so the entry should pass-through again
+	l.WithField("host", "a").Error("connection refused")
+	require.Len(t, emitter.Entries, 5)
+}
+
+func TestHookZapContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	zapLogger := upstreamzap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(upstreamzap.NewProductionEncoderConfig()),
+		zapcore.AddSync(&buf),
+		upstreamzap.InfoLevel,
+	))
+	l := zap.New(zapLogger, types.OptionGetCallerFunc(nil))
+	l = l.WithHooks(New(l.Emitter(), time.Hour, OptionKeyFields{"host"}))
+
+	for i := 0; i < 5; i++ {
+		l.WithField("host", "a").Error("connection refused")
+	}
+	l.WithField("host", "b").Error("connection refused")
+	require.Equal(t, 2, strings.Count(buf.String(), "\n"), buf.String())
+	require.Equal(t, 1, strings.Count(buf.String(), `"host":"a"`), buf.String())
+	require.Equal(t, 1, strings.Count(buf.String(), `"host":"b"`), buf.String())
+
+	l.Flush(context.Background())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[2], `"host":"a"`)
+	require.Contains(t, lines[2], `"repeated":4`)
+}
+
+func TestHookWindowClose(t *testing.T) {
+	emitter := &dummyEmitter{}
+	hook := New(emitter, time.Millisecond)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, i == 0, hook.ProcessLogEntry(&types.Entry{Level: types.LevelInfo, Message: "flap"}))
+	}
+	require.Eventually(t, func() bool {
+		return emitter.Len() == 1
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package deduplicator
+
+import (
+	"github.com/facebookincubator/go-belt/pkg/field"
+)
+
+// Option is an optional argument to function New, that changes the behavior of the Hook.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		RepeatedKey: DefaultRepeatedKey,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	KeyFields   []field.Key
+	RepeatedKey field.Key
+}
+
+// OptionKeyFields defines which fields (in addition to the message and
+// the logging level) should be equal for entries to be considered repeats
+// of each other.
+//
+// By default fields are not compared at all.
+//
+// See the description of Hook on which context fields could be compared.
+type OptionKeyFields []field.Key
+
+func (opt OptionKeyFields) apply(cfg *config) {
+	cfg.KeyFields = opt
+}
+
+// OptionRepeatedKey overrides the field name used to report the amount
+// of suppressed entries in the summary entry (see DefaultRepeatedKey).
+type OptionRepeatedKey field.Key
+
+func (opt OptionRepeatedKey) apply(cfg *config) {
+	cfg.RepeatedKey = field.Key(opt)
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/redact"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Hook is a types.Hook implementation which redacts sensitive data
+// in messages and fields of entries (see package redact).
+//
+// Setup example:
+//
+//	import (
+//		"github.com/facebookincubator/go-belt/pkg/redact"
+//		redacthook "github.com/facebookincubator/go-belt/tool/logger/hooks/redact"
+//		"github.com/facebookincubator/go-belt/tool/logger/implementation/zap"
+//	)
+//
+//	func main() {
+//		...
+//		l := zap.Default().WithHooks(redacthook.New(redact.New(redact.OptionStrategy(redact.StrategyHash))))
+//		ctx = logger.CtxWithLogger(ctx, l)
+//		...
+//	}
+type Hook struct {
+	Redactor *redact.Redactor
+}
+
+var _ types.ContextFieldsHook = (*Hook)(nil)
+
+// New returns a new instance of Hook.
+func New(redactor *redact.Redactor) *Hook {
+	return &Hook{
+		Redactor: redactor,
+	}
+}
+
+// ProcessLogEntry implements types.Hook.
+func (hook *Hook) ProcessLogEntry(entry *types.Entry) bool {
+	entry.Message = hook.Redactor.RedactString(entry.Message)
+	entry.Fields, _ = hook.Redactor.RedactFields(entry.Fields)
+	return true
+}
+
+// ProcessContextFields implements types.ContextFieldsHook.
+func (hook *Hook) ProcessContextFields(fields field.AbstractFields) field.AbstractFields {
+	fields, _ = hook.Redactor.RedactFields(fields)
+	return fields
+}
+
+// Flush implements types.Hook.
+func (hook *Hook) Flush() {}
@@ -0,0 +1,51 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/redact"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	hook := New(redact.New())
+	entry := &types.Entry{
+		Message: "password reset requested by john.doe@example.com",
+		Fields: field.Fields{
+			{Key: "password", Value: "qwerty"},
+			{Key: "user_id", Value: 42},
+		},
+	}
+	require.True(t, hook.ProcessLogEntry(entry))
+	require.Equal(t, "password reset requested by <redacted>", entry.Message)
+	require.Equal(t, field.Fields{
+		{Key: "password", Value: "<redacted>"},
+		{Key: "user_id", Value: 42},
+	}, field.Gather(entry.Fields))
+}
+
+func TestHookContextFields(t *testing.T) {
+	hook := New(redact.New())
+	fields := field.Fields{
+		{Key: "password", Value: "qwerty"},
+		{Key: "user_id", Value: 42},
+	}
+	require.Equal(t, field.Fields{
+		{Key: "password", Value: "<redacted>"},
+		{Key: "user_id", Value: 42},
+	}, field.Gather(hook.ProcessContextFields(fields)))
+}
@@ -0,0 +1,301 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package sanitizer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+var (
+	// DefaultMaxMessageLength is the default maximal length (in bytes) of a message.
+	DefaultMaxMessageLength = 32 * 1024
+
+	// DefaultMaxFieldValueLength is the default maximal length (in bytes) of
+	// a field key and of a string field value (see Hook).
+	DefaultMaxFieldValueLength = 4 * 1024
+
+	// DefaultMaxFields is the default maximal amount of fields of an entry.
+	DefaultMaxFields = 256
+
+	// DefaultTruncatedKey is the default field name used to mark truncated entries.
+	DefaultTruncatedKey = "truncated"
+)
+
+// Hook is a types.Hook implementation which protects the logging pipeline
+// from untrusted input (log injection and oversized entries).
+//
+// It escapes control characters (including newlines) in messages,
+// field keys and string field values (Go-style: "\n", "\x1b", "\u2028"),
+// and truncates oversized messages, keys, string values and collections
+// of fields (the limits apply to the escaped strings). Truncated entries are
+// marked with field "truncated=true" (see OptionTruncatedKey), so that
+// downstream tooling knows the entry is incomplete.
+//
+// Errors, fmt.Stringer-s and []byte-s are sanitized by their string form (lazy
+// values are resolved first, see field.LazyValue): if
+// it is changed, then the value is replaced by the sanitized string (an error
+// remains an error, which wraps the original one). Other values which are not
+// strings are passed as is.
+//
+// Setup example:
+//
+//	import (
+//		"github.com/facebookincubator/go-belt/tool/logger/hooks/sanitizer"
+//		"github.com/facebookincubator/go-belt/tool/logger/implementation/zap"
+//	)
+//
+//	func main() {
+//		...
+//		l := zap.Default().WithHooks(sanitizer.New(sanitizer.OptionMaxMessageLength(4096)))
+//		ctx = logger.CtxWithLogger(ctx, l)
+//		...
+//	}
+type Hook struct {
+	Escape              bool
+	MaxMessageLength    int
+	MaxFieldValueLength int
+	MaxFields           int
+	TruncatedKey        field.Key
+}
+
+var _ types.ContextFieldsHook = (*Hook)(nil)
+
+// New returns a new instance of Hook.
+func New(opts ...Option) *Hook {
+	cfg := options(opts).Config()
+	return &Hook{
+		Escape:              cfg.Escape,
+		MaxMessageLength:    cfg.MaxMessageLength,
+		MaxFieldValueLength: cfg.MaxFieldValueLength,
+		MaxFields:           cfg.MaxFields,
+		TruncatedKey:        cfg.TruncatedKey,
+	}
+}
+
+// ProcessLogEntry implements types.Hook.
+func (hook *Hook) ProcessLogEntry(entry *types.Entry) bool {
+	var truncated bool
+	entry.Message, truncated = hook.sanitize(entry.Message, hook.MaxMessageLength)
+
+	if entry.Fields != nil && hook.needsSanitizingFields(entry.Fields) {
+		fields, fieldsTruncated := hook.sanitizeFields(entry.Fields)
+		entry.Fields = fields
+		truncated = truncated || fieldsTruncated
+	}
+
+	if truncated {
+		entry.Fields = field.Add(entry.Fields, &field.Field{Key: hook.TruncatedKey, Value: true})
+	}
+	return true
+}
+
+// ProcessContextFields implements types.ContextFieldsHook.
+func (hook *Hook) ProcessContextFields(fields field.AbstractFields) field.AbstractFields {
+	if fields == nil || !hook.needsSanitizingFields(fields) {
+		return fields
+	}
+	sanitized, truncated := hook.sanitizeFields(fields)
+	if truncated {
+		sanitized = append(sanitized, field.Field{Key: hook.TruncatedKey, Value: true})
+	}
+	return sanitized
+}
+
+// Flush implements types.Hook.
+func (hook *Hook) Flush() {}
+
+func (hook *Hook) needsSanitizingFields(fields field.AbstractFields) bool {
+	count := 0
+	result := false
+	fields.ForEachField(func(f *field.Field) bool {
+		count++
+		if hook.MaxFields > 0 && count > hook.MaxFields {
+			result = true
+			return false
+		}
+		if hook.needsSanitizing(f.Key, hook.MaxFieldValueLength) {
+			result = true
+			return false
+		}
+		if value, ok := valueString(field.ResolveValue(f.Value)); ok && hook.needsSanitizing(value, hook.MaxFieldValueLength) {
+			result = true
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+func (hook *Hook) sanitizeFields(fields field.AbstractFields) (field.Fields, bool) {
+	truncated := false
+	result := make(field.Fields, 0, fields.Len())
+	fields.ForEachField(func(f *field.Field) bool {
+		if hook.MaxFields > 0 && len(result) >= hook.MaxFields {
+			truncated = true
+			return false
+		}
+		sanitized := *f
+		var keyTruncated, valueTruncated bool
+		sanitized.Key, keyTruncated = hook.sanitize(f.Key, hook.MaxFieldValueLength)
+		value := field.ResolveValue(f.Value)
+		if s, ok := valueString(value); ok && hook.needsSanitizing(s, hook.MaxFieldValueLength) {
+			s, valueTruncated = hook.sanitize(s, hook.MaxFieldValueLength)
+			if err, ok := value.(error); ok {
+				sanitized.Value = &sanitizedError{Message: s, Err: err}
+			} else {
+				sanitized.Value = s
+			}
+		}
+		truncated = truncated || keyTruncated || valueTruncated
+		result = append(result, sanitized)
+		return true
+	})
+	return result, truncated
+}
+
+func (hook *Hook) needsSanitizing(s string, maxLength int) bool {
+	if maxLength > 0 && len(s) > maxLength {
+		return true
+	}
+	return hook.Escape && needsEscaping(s)
+}
+
+// sanitize escapes and truncates the string. It returns true if the string was truncated.
+//
+// The limit applies to the escaped string, and neither a rune nor an escape
+// sequence is cut.
+func (hook *Hook) sanitize(s string, maxLength int) (string, bool) {
+	if !hook.needsSanitizing(s, maxLength) {
+		return s, false
+	}
+	if !hook.Escape {
+		cut := maxLength
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		return s[:cut], true
+	}
+	var result strings.Builder
+	if maxLength > 0 && maxLength < len(s) {
+		result.Grow(maxLength)
+	} else {
+		result.Grow(len(s) + 16)
+	}
+	truncated := escape(&result, s, maxLength)
+	return result.String(), truncated
+}
+
+// sanitizedError is an error with a sanitized message, wrapping the original error.
+type sanitizedError struct {
+	Message string
+	Err     error
+}
+
+func (err *sanitizedError) Error() string {
+	return err.Message
+}
+
+func (err *sanitizedError) Unwrap() error {
+	return err.Err
+}
+
+// valueString returns the string form of a value which should be sanitized,
+// or false if the value is passed as is.
+func valueString(value field.Value) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case []byte:
+		return string(value), true
+	case error, fmt.Stringer:
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", false
+		}
+		if err, ok := value.(error); ok {
+			return err.Error(), true
+		}
+		return value.(fmt.Stringer).String(), true
+	}
+	return "", false
+}
+
+func isUnsafeRune(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029'
+}
+
+func needsEscaping(s string) bool {
+	for idx := 0; idx < len(s); {
+		r, size := utf8.DecodeRuneInString(s[idx:])
+		if (r == utf8.RuneError && size == 1) || isUnsafeRune(r) {
+			return true
+		}
+		idx += size
+	}
+	return false
+}
+
+// Escape returns the string with control characters, Unicode line separators
+// and invalid UTF-8 bytes replaced by Go-like escape sequences (for example "\n" or "\x1b"),
+// so that the string could be safely printed to a terminal. It is used by Hook
+// if Escape is enabled.
+func Escape(s string) string {
+	if !needsEscaping(s) {
+		return s
+	}
+	var result strings.Builder
+	result.Grow(len(s) + 16)
+	escape(&result, s, 0)
+	return result.String()
+}
+
+const hexDigits = "0123456789abcdef"
+
+// escape writes the escaped string to the result, stopping before the
+// result would exceed maxLength (if it is positive). It returns true if
+// the string was truncated.
+func escape(result *strings.Builder, s string, maxLength int) bool {
+	var buf [utf8.UTFMax + 2]byte
+	for idx := 0; idx < len(s); {
+		r, size := utf8.DecodeRuneInString(s[idx:])
+		piece := buf[:0]
+		switch {
+		case r == utf8.RuneError && size == 1:
+			piece = append(piece, '\\', 'x', hexDigits[s[idx]>>4], hexDigits[s[idx]&0xf])
+		case r == '\n':
+			piece = append(piece, '\\', 'n')
+		case r == '\r':
+			piece = append(piece, '\\', 'r')
+		case r == '\t':
+			piece = append(piece, '\\', 't')
+		case isUnsafeRune(r) && r < 0x100:
+			piece = append(piece, '\\', 'x', hexDigits[r>>4], hexDigits[r&0xf])
+		case isUnsafeRune(r):
+			piece = append(piece, '\\', 'u', hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+		default:
+			piece = append(piece, s[idx:idx+size]...)
+		}
+		if maxLength > 0 && result.Len()+len(piece) > maxLength {
+			return true
+		}
+		result.Write(piece)
+		idx += size
+	}
+	return false
+}
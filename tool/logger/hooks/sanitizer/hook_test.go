@@ -0,0 +1,139 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package sanitizer
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookEscape(t *testing.T) {
+	hook := New()
+	entry := &types.Entry{
+		Message: "user logged in\nINFO fake entry\x1b[31m",
+		Fields: field.Fields{
+			{Key: "user\r\nname", Value: "admin\u2028"},
+			{Key: "count", Value: 1},
+		},
+	}
+	require.True(t, hook.ProcessLogEntry(entry))
+	require.Equal(t, `user logged in\nINFO fake entry\x1b[31m`, entry.Message)
+	require.Equal(t, field.Fields{
+		{Key: `user\r\nname`, Value: `admin\u2028`},
+		{Key: "count", Value: 1},
+	}, field.Gather(entry.Fields))
+}
+
+func TestHookNoChanges(t *testing.T) {
+	hook := New()
+	fields := field.Fields{{Key: "key", Value: "value"}}
+	entry := &types.Entry{Message: "message", Fields: fields}
+	require.True(t, hook.ProcessLogEntry(entry))
+	require.Equal(t, "message", entry.Message)
+	require.Equal(t, fields, entry.Fields)
+}
+
+func TestHookTruncate(t *testing.T) {
+	hook := New(
+		OptionMaxMessageLength(8),
+		OptionMaxFieldValueLength(4),
+		OptionMaxFields(2),
+		OptionTruncatedKey("cut"),
+	)
+	entry := &types.Entry{
+		Message: "12345678ä",
+		Fields: field.Fields{
+			{Key: "a", Value: "ab€"},
+			{Key: "b", Value: strings.Repeat("x", 10)},
+			{Key: "c", Value: "c"},
+		},
+	}
+	require.True(t, hook.ProcessLogEntry(entry))
+	require.Equal(t, "12345678", entry.Message)
+	require.Equal(t, field.Fields{
+		{Key: "a", Value: "ab"},
+		{Key: "b", Value: "xxxx"},
+		{Key: "cut", Value: true},
+	}, field.Gather(entry.Fields))
+}
+
+func TestHookTruncateEscaped(t *testing.T) {
+	hook := New(OptionMaxMessageLength(4), OptionMaxFieldValueLength(6))
+	entry := &types.Entry{
+		Message: "ab\ncd",
+		Fields:  field.Fields{{Key: "a", Value: strings.Repeat("\x1b", 10)}},
+	}
+	require.True(t, hook.ProcessLogEntry(entry))
+	require.Equal(t, `ab\n`, entry.Message)
+	require.Equal(t, field.Fields{
+		{Key: "a", Value: `\x1b`},
+		{Key: "truncated", Value: true},
+	}, field.Gather(entry.Fields))
+
+	entry = &types.Entry{Message: "a\nbc"}
+	require.True(t, hook.ProcessLogEntry(entry))
+	require.Equal(t, `a\nb`, entry.Message)
+}
+
+type testStringer string
+
+func (s testStringer) String() string {
+	return string(s)
+}
+
+func TestHookNonStringValues(t *testing.T) {
+	hook := New()
+	var nilURL *url.URL
+	err := errors.New("unable to log in\nINFO fake entry")
+	entry := &types.Entry{
+		Fields: field.Fields{
+			{Key: "error", Value: err},
+			{Key: "stringer", Value: testStringer("a\nb")},
+			{Key: "body", Value: []byte("a\nb")},
+			{Key: "lazy", Value: field.Lazy(func() any { return "a\nb" })},
+			{Key: "nil_url", Value: nilURL},
+			{Key: "clean_error", Value: io.EOF},
+		},
+	}
+	require.True(t, hook.ProcessLogEntry(entry))
+	fields := field.Gather(entry.Fields)
+	require.EqualError(t, fields[0].Value.(error), `unable to log in\nINFO fake entry`)
+	require.ErrorIs(t, fields[0].Value.(error), err)
+	require.Equal(t, field.Fields{
+		{Key: "stringer", Value: `a\nb`},
+		{Key: "body", Value: `a\nb`},
+		{Key: "lazy", Value: `a\nb`},
+		{Key: "nil_url", Value: nilURL},
+		{Key: "clean_error", Value: io.EOF},
+	}, fields[1:])
+}
+
+func TestHookContextFields(t *testing.T) {
+	hook := New(OptionMaxFields(1))
+	fields := field.Fields{
+		{Key: "user", Value: "admin\nINFO fake entry"},
+		{Key: "count", Value: 1},
+	}
+	require.Equal(t, field.Fields{
+		{Key: "user", Value: `admin\nINFO fake entry`},
+		{Key: "truncated", Value: true},
+	}, field.Gather(hook.ProcessContextFields(fields)))
+	require.Nil(t, hook.ProcessContextFields(nil))
+}
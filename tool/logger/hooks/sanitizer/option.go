@@ -0,0 +1,89 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package sanitizer
+
+import (
+	"github.com/facebookincubator/go-belt/pkg/field"
+)
+
+// Option is an optional argument to function New, that changes the behavior of the Hook.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		Escape:              true,
+		MaxMessageLength:    DefaultMaxMessageLength,
+		MaxFieldValueLength: DefaultMaxFieldValueLength,
+		MaxFields:           DefaultMaxFields,
+		TruncatedKey:        DefaultTruncatedKey,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	Escape              bool
+	MaxMessageLength    int
+	MaxFieldValueLength int
+	MaxFields           int
+	TruncatedKey        field.Key
+}
+
+// OptionEscape enables or disables escaping of control characters
+// (including newlines) in messages, field keys and string field values.
+//
+// Enabled by default.
+type OptionEscape bool
+
+func (opt OptionEscape) apply(cfg *config) {
+	cfg.Escape = bool(opt)
+}
+
+// OptionMaxMessageLength overrides the maximal length (in bytes, after escaping)
+// of a message (see DefaultMaxMessageLength). Zero or a negative value disables the limit.
+type OptionMaxMessageLength int
+
+func (opt OptionMaxMessageLength) apply(cfg *config) {
+	cfg.MaxMessageLength = int(opt)
+}
+
+// OptionMaxFieldValueLength overrides the maximal length (in bytes, after escaping) of
+// a field key and of a string field value (see DefaultMaxFieldValueLength).
+// Zero or a negative value disables the limit.
+type OptionMaxFieldValueLength int
+
+func (opt OptionMaxFieldValueLength) apply(cfg *config) {
+	cfg.MaxFieldValueLength = int(opt)
+}
+
+// OptionMaxFields overrides the maximal amount of fields of an entry
+// (see DefaultMaxFields). Zero or a negative value disables the limit.
+type OptionMaxFields int
+
+func (opt OptionMaxFields) apply(cfg *config) {
+	cfg.MaxFields = int(opt)
+}
+
+// OptionTruncatedKey overrides the field name used to mark truncated
+// entries (see DefaultTruncatedKey).
+type OptionTruncatedKey field.Key
+
+func (opt OptionTruncatedKey) apply(cfg *config) {
+	cfg.TruncatedKey = field.Key(opt)
+}
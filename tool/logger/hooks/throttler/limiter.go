@@ -0,0 +1,108 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package throttler
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Limiter defines if a next entry fits into the budget.
+type Limiter interface {
+	// Allow returns true if the next entry fits into the budget (and
+	// consumes the budget).
+	Allow() bool
+}
+
+// Every is a Limiter which allows each N-th entry, starting from the first one
+// (similar to LOG_EVERY_N of glog).
+type Every struct {
+	// N is the N of ("each N-th entry").
+	N uint64
+
+	// Count is the amount of entries were met already.
+	Count uint64
+}
+
+var _ Limiter = (*Every)(nil)
+
+// NewEvery returns a new instance of Every.
+func NewEvery(n uint64) *Every {
+	return &Every{N: n}
+}
+
+// Allow implements Limiter.
+func (l *Every) Allow() bool {
+	if l.N <= 1 {
+		return true
+	}
+	return (atomic.AddUint64(&l.Count, 1)-1)%l.N == 0
+}
+
+// FirstN is a Limiter which allows only the first N entries
+// (similar to LOG_FIRST_N of glog).
+type FirstN struct {
+	// N is the amount of entries to be allowed.
+	N uint64
+
+	// Count is the amount of entries were met already.
+	Count uint64
+}
+
+var _ Limiter = (*FirstN)(nil)
+
+// NewFirstN returns a new instance of FirstN.
+func NewFirstN(n uint64) *FirstN {
+	return &FirstN{N: n}
+}
+
+// Allow implements Limiter.
+func (l *FirstN) Allow() bool {
+	if atomic.LoadUint64(&l.Count) >= l.N {
+		// to avoid overflowing the counter
+		return false
+	}
+	return atomic.AddUint64(&l.Count, 1) <= l.N
+}
+
+// Per is a Limiter which allows at most one entry per Interval.
+type Per struct {
+	// Interval is the minimal interval between two allowed entries.
+	Interval time.Duration
+
+	// LastAllowed is the UnixNano timestamp of the last allowed entry.
+	LastAllowed int64
+}
+
+var _ Limiter = (*Per)(nil)
+
+// NewPer returns a new instance of Per.
+func NewPer(interval time.Duration) *Per {
+	return &Per{Interval: interval}
+}
+
+var timeNow = time.Now
+
+// Allow implements Limiter.
+func (l *Per) Allow() bool {
+	now := timeNow().UnixNano()
+	for {
+		last := atomic.LoadInt64(&l.LastAllowed)
+		if last != 0 && now-last < int64(l.Interval) {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&l.LastAllowed, last, now) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func allowed(l Limiter, count int) []int {
+	var result []int
+	for i := 0; i < count; i++ {
+		if l.Allow() {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func TestEvery(t *testing.T) {
+	require.Equal(t, []int{0, 3, 6, 9}, allowed(NewEvery(3), 10))
+	require.Equal(t, []int{0, 1, 2}, allowed(NewEvery(0), 3))
+}
+
+func TestFirstN(t *testing.T) {
+	require.Equal(t, []int{0, 1, 2}, allowed(NewFirstN(3), 10))
+	require.Empty(t, allowed(NewFirstN(0), 10))
+}
+
+func TestPer(t *testing.T) {
+	now := time.Unix(1, 0)
+	timeNow = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	l := NewPer(time.Minute)
+	require.True(t, l.Allow())
+	require.False(t, l.Allow())
+	now = now.Add(time.Minute - 1)
+	require.False(t, l.Allow())
+	now = now.Add(1)
+	require.True(t, l.Allow())
+}
+
+func TestGetPreHook(t *testing.T) {
+	newLimiter := func() Limiter {
+		return NewFirstN(1)
+	}
+	hook := GetPreHook(t.Name(), newLimiter)
+	require.False(t, hook.ProcessInput(nil, 0).Skip)
+	require.True(t, GetPreHook(t.Name(), newLimiter).ProcessInput(nil, 0).Skip)
+	ResetPreHook(t.Name())
+	require.False(t, GetPreHook(t.Name(), newLimiter).ProcessInput(nil, 0).Skip)
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package throttler
+
+import (
+	"sync"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// PreHook is a types.PreHook implementation which drops the log entries
+// which does not fit into the budget defined by the Limiter.
+//
+// It is expected to be used through functions logger.Every, logger.FirstN
+// and logger.Per, which keep a separate Limiter per each line of code.
+//
+// Setup example:
+//
+//	for _, item := range items {
+//		logger.Every(ctx, 100).Debugf("processing item %v", item)
+//	}
+type PreHook struct {
+	Limiter
+}
+
+var _ types.PreHook = (*PreHook)(nil)
+
+// NewPreHook returns a new instance of PreHook.
+func NewPreHook(limiter Limiter) *PreHook {
+	return &PreHook{
+		Limiter: limiter,
+	}
+}
+
+// ProcessInput implements types.PreHook.
+func (hook *PreHook) ProcessInput(belt.TraceIDs, types.Level, ...any) types.PreHookResult {
+	return types.PreHookResult{
+		Skip: !hook.Limiter.Allow(),
+	}
+}
+
+// ProcessInputf implements types.PreHook.
+func (hook *PreHook) ProcessInputf(belt.TraceIDs, types.Level, string, ...any) types.PreHookResult {
+	return types.PreHookResult{
+		Skip: !hook.Limiter.Allow(),
+	}
+}
+
+// ProcessInputFields implements types.PreHook.
+func (hook *PreHook) ProcessInputFields(belt.TraceIDs, types.Level, string, field.AbstractFields) types.PreHookResult {
+	return types.PreHookResult{
+		Skip: !hook.Limiter.Allow(),
+	}
+}
+
+var preHooks sync.Map
+
+// GetPreHook returns the PreHook associated with the given key. If there is
+// no such PreHook, yet, then it is created using the Limiter returned by newLimiter.
+//
+// The key could be anything comparable, for example the program counter of
+// the line of code which issues the log entries.
+func GetPreHook(key any, newLimiter func() Limiter) *PreHook {
+	if hook, ok := preHooks.Load(key); ok {
+		return hook.(*PreHook)
+	}
+	hook, _ := preHooks.LoadOrStore(key, NewPreHook(newLimiter()))
+	return hook.(*PreHook)
+}
+
+// ResetPreHook forgets the PreHook associated with the given key, so the
+// budget will be started over.
+func ResetPreHook(key any) {
+	preHooks.Delete(key)
+}
@@ -0,0 +1,236 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package audit implements a tamper-evident audit log.
+//
+// Each record is a JSON object (one per line) containing the sequence number
+// of the record, the hash of the previous record, the entry and the hash
+// (or HMAC, see OptionHMACKey) of the record itself:
+//
+//	{"seq":2,"prev":"9f86…","entry":{"ts":"…","level":"info","msg":"user deleted","user_id":42},"hash":"60303…"}
+//
+// The entry is stored under its own key, so its fields cannot be confused
+// with the fields of the record.
+//
+// Thus any modification, removal or insertion of a record breaks the chain,
+// which is detected by Verify. Note: removal of the latest records could be
+// detected only by comparing the result of Verify with a previously
+// saved (anchored elsewhere) sequence number and hash.
+package audit
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/logstore"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Emitter is a types.Emitter implementation, which writes entries marked
+// with EntryPropertyAudit into a tamper-evident audit log file.
+//
+// Since it ignores other entries, it is supposed to be used together
+// with the usual Emitters, for example:
+//
+//	auditEmitter, err := audit.NewEmitter("/var/log/myapp/audit.log", audit.OptionHMACKey(key))
+//	...
+//	l := adapter.LoggerFromEmitter(types.Emitters{auditEmitter, zap.NewEmitter(zapLogger)})
+//	...
+//	audit.Logger(l).InfoFields("user deleted", field.Fields{{Key: "user_id", Value: userID}})
+type Emitter struct {
+	config config
+
+	locker   sync.Mutex
+	file     *os.File
+	seq      uint64
+	prevHash string
+	unsynced uint
+}
+
+var _ types.Emitter = (*Emitter)(nil)
+
+// NewEmitter opens (or creates) the audit log file and returns an Emitter
+// which appends records to it.
+//
+// An existing file is verified first (see Verify) and an error is returned
+// if it is not valid. An incomplete last record (left by a crash) is removed.
+func NewEmitter(path string, opts ...Option) (*Emitter, error) {
+	cfg := options(opts).Config()
+	if err := truncateIncompleteRecord(path); err != nil {
+		return nil, fmt.Errorf("unable to remove an incomplete record from '%s': %w", path, err)
+	}
+
+	state, err := VerifyFile(path, opts...)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("the existing audit log '%s' is not valid: %w", path, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Emitter{
+		config:   cfg,
+		file:     file,
+		seq:      state.LastSeq,
+		prevHash: state.LastHash,
+	}, nil
+}
+
+// New returns a types.Logger which writes audit records (all its entries
+// are marked with EntryPropertyAudit) into the Emitter.
+func New(emitter *Emitter, opts ...types.Option) types.Logger {
+	return Logger(adapter.LoggerFromEmitter(emitter, opts...))
+}
+
+// Emit implements types.Emitter.
+func (e *Emitter) Emit(entry *types.Entry) {
+	if !e.config.AllEntries && !entry.Properties.Has(EntryPropertyAudit) {
+		return
+	}
+	entryJSON := logstore.MarshalEntry(entry)
+
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	if e.file == nil {
+		e.config.ErrorHandler(fmt.Errorf("the audit log is closed, the record is lost: %s", entryJSON))
+		return
+	}
+
+	seq := e.seq + 1
+	body := make([]byte, 0, len(entryJSON)+200)
+	body = append(body, `{"seq":`...)
+	body = strconv.AppendUint(body, seq, 10)
+	body = append(body, `,"prev":"`...)
+	body = append(body, e.prevHash...)
+	body = append(body, `","entry":`...)
+	body = append(body, entryJSON...)
+	recordHash := sum(newHash(e.config.HMACKey), body)
+	record := append(body, `,"hash":"`...)
+	record = append(record, recordHash...)
+	record = append(record, "\"}\n"...)
+
+	if _, err := e.file.Write(record); err != nil {
+		e.config.ErrorHandler(fmt.Errorf("unable to write a record: %w", err))
+		return
+	}
+	e.seq = seq
+	e.prevHash = recordHash
+
+	e.unsynced++
+	if e.config.SyncEvery > 0 && e.unsynced >= e.config.SyncEvery {
+		e.sync()
+	}
+}
+
+func (e *Emitter) sync() {
+	if err := e.file.Sync(); err != nil {
+		e.config.ErrorHandler(fmt.Errorf("unable to sync: %w", err))
+		return
+	}
+	e.unsynced = 0
+}
+
+// Flush implements types.Emitter. It syncs the file to the disk.
+func (e *Emitter) Flush() {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	if e.file != nil && e.unsynced > 0 {
+		e.sync()
+	}
+}
+
+// Close syncs and closes the file.
+func (e *Emitter) Close() error {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Sync()
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	e.file = nil
+	return err
+}
+
+// State returns the sequence number and the hash of the latest record.
+// They could be anchored elsewhere to detect removal of the latest records.
+func (e *Emitter) State() (seq uint64, hash string) {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	return e.seq, e.prevHash
+}
+
+func newHash(hmacKey []byte) hash.Hash {
+	if hmacKey != nil {
+		return hmac.New(sha256.New, hmacKey)
+	}
+	return sha256.New()
+}
+
+func sum(h hash.Hash, body []byte) string {
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// truncateIncompleteRecord removes the trailing bytes after the last newline.
+func truncateIncompleteRecord(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := stat.Size()
+	const chunkSize = 4096
+	buf := make([]byte, chunkSize)
+	for end := size; end > 0; {
+		start := end - chunkSize
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if idx := bytes.LastIndexByte(chunk, '\n'); idx >= 0 {
+			if newSize := start + int64(idx) + 1; newSize != size {
+				return f.Truncate(newSize)
+			}
+			return nil
+		}
+		end = start
+	}
+	if size > 0 {
+		return f.Truncate(0)
+	}
+	return nil
+}
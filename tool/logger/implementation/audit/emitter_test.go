@@ -0,0 +1,147 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestLog(t *testing.T, path string, opts ...Option) {
+	emitter, err := NewEmitter(path, opts...)
+	require.NoError(t, err)
+	l := adapter.LoggerFromEmitter(emitter).WithLevel(types.LevelTrace)
+	l.Info("not an audit record")
+	for _, userID := range []int{1, 2, 3} {
+		Logger(l).InfoFields("user deleted", field.Fields{{Key: "user_id", Value: userID}})
+	}
+	require.NoError(t, emitter.Close())
+}
+
+func TestEmitterAndVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	key := OptionHMACKey("secret")
+	writeTestLog(t, path, key)
+
+	result, err := VerifyFile(path, key)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Records)
+	require.Equal(t, uint64(3), result.LastSeq)
+
+	_, err = VerifyFile(path, OptionHMACKey("wrong"))
+	require.Error(t, err)
+
+	// continuing the chain after reopening
+	emitter, err := NewEmitter(path, key)
+	require.NoError(t, err)
+	New(emitter).WithLevel(types.LevelTrace).Warn("settings changed")
+	seq, hash := emitter.State()
+	require.NoError(t, emitter.Close())
+	result, err = VerifyFile(path, key)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), seq)
+	require.Equal(t, VerifyResult{Records: 4, LastSeq: 4, LastHash: hash}, result)
+}
+
+func TestEmitterReservedKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	emitter, err := NewEmitter(path)
+	require.NoError(t, err)
+	New(emitter).WithLevel(types.LevelTrace).InfoFields("user deleted", field.Fields{
+		{Key: "seq", Value: 7},
+		{Key: "prev", Value: "forged"},
+		{Key: "hash", Value: "forged"},
+	})
+	require.NoError(t, emitter.Close())
+
+	result, err := VerifyFile(path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), result.LastSeq)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"entry":{`)
+	require.Contains(t, string(content), `"seq":7`)
+
+	// the log could be reopened and continued
+	emitter, err = NewEmitter(path)
+	require.NoError(t, err)
+	New(emitter).WithLevel(types.LevelTrace).Info("settings changed")
+	require.NoError(t, emitter.Close())
+	result, err = VerifyFile(path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.LastSeq)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeTestLog(t, path)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(string(content), "\n")
+	lines = lines[:len(lines)-1]
+	require.Len(t, lines, 3)
+
+	for name, tampered := range map[string]string{
+		"modified":  lines[0] + strings.Replace(lines[1], `"user_id":2`, `"user_id":5`, 1) + lines[2],
+		"removed":   lines[0] + lines[2],
+		"reordered": lines[0] + lines[2] + lines[1],
+		"truncated": lines[1] + lines[2],
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(tampered))
+			var verificationErr *VerificationError
+			require.True(t, errors.As(err, &verificationErr), err)
+
+			require.NoError(t, os.WriteFile(path+"."+name, []byte(tampered), 0600))
+			_, err = NewEmitter(path + "." + name)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEmitterIncompleteRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeTestLog(t, path, OptionSyncEvery(2))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":4,"prev":"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	emitter, err := NewEmitter(path)
+	require.NoError(t, err)
+	emitter.Emit(&types.Entry{
+		Timestamp:  time.Now(),
+		Level:      types.LevelInfo,
+		Message:    "after a crash",
+		Properties: types.EntryProperties{EntryPropertyAudit},
+	})
+	require.NoError(t, emitter.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	result, err := Verify(bytes.NewReader(content))
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), result.Records)
+}
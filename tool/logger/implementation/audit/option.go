@@ -0,0 +1,82 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package audit
+
+import (
+	"fmt"
+	"os"
+)
+
+// Option is an optional argument to functions NewEmitter and Verify.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		SyncEvery: 1,
+		ErrorHandler: func(err error) {
+			fmt.Fprintf(os.Stderr, "audit: %v\n", err)
+		},
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	HMACKey      []byte
+	SyncEvery    uint
+	AllEntries   bool
+	ErrorHandler func(error)
+}
+
+// OptionHMACKey makes records signed with HMAC-SHA256 using the given key
+// (instead of a plain SHA-256 hash). Without the key it is impossible
+// to forge the chain, even by rewriting the whole file.
+//
+// The same key should be passed to Verify.
+type OptionHMACKey []byte
+
+func (opt OptionHMACKey) apply(cfg *config) {
+	cfg.HMACKey = opt
+}
+
+// OptionSyncEvery defines how many records are written before the file
+// is synced to the disk (fsync). The default value is 1 (sync each record),
+// zero value means to sync only on Flush.
+type OptionSyncEvery uint
+
+func (opt OptionSyncEvery) apply(cfg *config) {
+	cfg.SyncEvery = uint(opt)
+}
+
+// OptionAllEntries makes Emitter write all the entries, not only
+// entries with EntryPropertyAudit.
+type OptionAllEntries bool
+
+func (opt OptionAllEntries) apply(cfg *config) {
+	cfg.AllEntries = bool(opt)
+}
+
+// OptionErrorHandler defines the function called on errors which could
+// not be returned (for example on a failed write in Emit).
+// By default errors are printed to stderr.
+type OptionErrorHandler func(error)
+
+func (opt OptionErrorHandler) apply(cfg *config) {
+	cfg.ErrorHandler = opt
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// VerifyResult is the summary of a successful verification.
+type VerifyResult struct {
+	// Records is the amount of verified records.
+	Records uint64
+
+	// LastSeq is the sequence number of the latest record.
+	LastSeq uint64
+
+	// LastHash is the hash of the latest record.
+	LastHash string
+}
+
+// VerificationError describes a detected violation of the audit log integrity.
+type VerificationError struct {
+	// Line is the number of the line (starting from 1) of the invalid record.
+	Line uint64
+
+	// Reason describes the violation.
+	Reason string
+}
+
+func (err *VerificationError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Reason)
+}
+
+var hashMarker = []byte(`,"hash":"`)
+
+// Verify reads the audit log and checks its integrity: the hashes (or HMACs,
+// see OptionHMACKey) of the records, their chaining and the continuity of
+// their sequence numbers. A *VerificationError is returned on the first
+// detected modification or gap.
+func Verify(r io.Reader, opts ...Option) (VerifyResult, error) {
+	cfg := options(opts).Config()
+	reader := bufio.NewReader(r)
+
+	var (
+		result VerifyResult
+		line   uint64
+	)
+	for {
+		record, err := reader.ReadBytes('\n')
+		if len(record) == 0 && errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return result, err
+		}
+		line++
+		fail := func(format string, args ...any) (VerifyResult, error) {
+			return result, &VerificationError{Line: line, Reason: fmt.Sprintf(format, args...)}
+		}
+
+		if record[len(record)-1] != '\n' {
+			return fail("an incomplete record")
+		}
+		record = record[:len(record)-1]
+		idx := bytes.LastIndex(record, hashMarker)
+		if idx < 0 || !bytes.HasSuffix(record, []byte(`"}`)) {
+			return fail("the record has no hash")
+		}
+		body := record[:idx]
+		recordHash := string(record[idx+len(hashMarker) : len(record)-2])
+		expectedHash := sum(newHash(cfg.HMACKey), body)
+		if !hmac.Equal([]byte(recordHash), []byte(expectedHash)) {
+			return fail("the hash does not match the content of the record (the record was modified)")
+		}
+
+		var header struct {
+			Seq  uint64 `json:"seq"`
+			Prev string `json:"prev"`
+		}
+		if err := json.Unmarshal(append(body[:len(body):len(body)], '}'), &header); err != nil {
+			return fail("unable to parse the record: %v", err)
+		}
+		if header.Seq != result.LastSeq+1 {
+			return fail("expected sequence number %d, but got %d (records are missing or reordered)", result.LastSeq+1, header.Seq)
+		}
+		if header.Prev != result.LastHash {
+			return fail("the record is not chained to the previous one")
+		}
+
+		result.Records++
+		result.LastSeq = header.Seq
+		result.LastHash = recordHash
+	}
+}
+
+// VerifyFile is the same as Verify, but reads the file by the given path.
+func VerifyFile(path string, opts ...Option) (VerifyResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	defer f.Close()
+	return Verify(f, opts...)
+}
@@ -55,13 +55,34 @@ func (l GLog) Emit(entry *types.Entry) {
 	case logger.LevelFatal:
 		glog.Exit(entry.Message)
 	default:
-		glog.Info("[UNKNOWN LOGGING LEVEL] " + entry.Message)
+		if entry.Level.IsBuiltin() || entry.Level.Builtin() <= types.LevelNone {
+			glog.Info("[UNKNOWN LOGGING LEVEL] " + entry.Message)
+			return
+		}
+		// a custom level, it never exits
+		switch SeverityFromLevel(entry.Level) {
+		case 0:
+			glog.Info(entry.Message)
+		case 1:
+			glog.Warning(entry.Message)
+		default:
+			glog.Error(entry.Message)
+		}
 	}
 }
 
 // Severity is the internal glog's logging level.
 type Severity glog.Level
 
+var levelMapping types.LevelMapping[Severity]
+
+// RegisterLevel sets glog's severity to be used for a custom level
+// (see types.RegisterLevel). Messages of custom levels are never logged
+// through glog.Fatal/glog.Exit, thus severity 3 is logged as an error.
+func RegisterLevel(level types.Level, severity Severity) {
+	levelMapping.Set(level, severity)
+}
+
 // SeverityFromLevel converts logger.Level to Severity.
 func SeverityFromLevel(level types.Level) Severity {
 	switch level {
@@ -75,7 +96,12 @@ func SeverityFromLevel(level types.Level) Severity {
 		return 2
 	case types.LevelPanic, types.LevelFatal:
 		return 3
-	default:
-		return 0
 	}
+	if severity, ok := levelMapping.To(level); ok {
+		return severity
+	}
+	if builtin := level.Builtin(); builtin != level && builtin > types.LevelNone {
+		return SeverityFromLevel(builtin)
+	}
+	return 0
 }
@@ -0,0 +1,90 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package logr provides integrations with github.com/go-logr/logr in both directions:
+//
+// * Emitter and New provide a Logger which sends entries to an arbitrary logr.Logger.
+// * LogSink and NewLogr provide a logr.Logger which sends entries to a go-belt Logger
+// (for example to pass it to Kubernetes' client-go or controller-runtime).
+package logr
+
+import (
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/go-logr/logr"
+)
+
+var (
+	// FieldNameTraceIDs is the key used to pass belt.TraceIDs to a logr.Logger.
+	FieldNameTraceIDs = "trace_id"
+)
+
+// Emitter is the implementation of types.Emitter based on a logr.Logger.
+type Emitter struct {
+	Logger logr.Logger
+}
+
+var _ types.Emitter = (*Emitter)(nil)
+
+// NewEmitter returns a new instance of Emitter.
+func NewEmitter(logrLogger logr.Logger) Emitter {
+	return Emitter{
+		Logger: logrLogger,
+	}
+}
+
+// New returns a new instance of types.Logger based on a logr.Logger.
+//
+// The logging level is set to the most verbose one enabled in the logr.Logger
+// (see LevelFromLogger), unless the Logger is bound to a LevelVar (see types.OptionLevelVar).
+func New(logrLogger logr.Logger, opts ...types.Option) types.Logger {
+	l := adapter.LoggerFromEmitter(NewEmitter(logrLogger), opts...)
+	if types.Options(opts).Config().LevelVar != nil {
+		return l
+	}
+	return l.WithLevel(LevelFromLogger(logrLogger))
+}
+
+// Flush implements types.Emitter.
+//
+// logr does not have buffers to be flushed, so it does nothing.
+func (Emitter) Flush() {}
+
+// Emit implements types.Emitter.
+func (e Emitter) Emit(entry *types.Entry) {
+	if entry.Level == types.LevelNone {
+		return
+	}
+
+	keysAndValues := keysAndValuesFromEntry(entry)
+	if IsErrorLevel(entry.Level) {
+		e.Logger.Error(nil, entry.Message, keysAndValues...)
+		return
+	}
+	e.Logger.V(VerbosityFromLevel(entry.Level)).Info(entry.Message, keysAndValues...)
+}
+
+func keysAndValuesFromEntry(entry *types.Entry) []any {
+	var keysAndValues []any
+	if entry.Fields != nil {
+		keysAndValues = make([]any, 0, 2*entry.Fields.Len()+2)
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			keysAndValues = append(keysAndValues, f.Key, field.ResolveValue(f.Value))
+			return true
+		})
+	}
+	if len(entry.TraceIDs) > 0 {
+		keysAndValues = append(keysAndValues, FieldNameTraceIDs, entry.TraceIDs)
+	}
+	return keysAndValues
+}
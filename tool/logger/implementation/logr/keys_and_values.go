@@ -0,0 +1,79 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logr
+
+import (
+	"fmt"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/go-logr/logr"
+)
+
+// MissingValue is the value used for the last key of KeysAndValues
+// with an odd number of items.
+var MissingValue = "<no-value>"
+
+// KeysAndValues is an implementation of field.AbstractFields based on
+// a logr-style list of alternating keys and values.
+//
+// Values implementing logr.Marshaler are marshaled on each iteration, so
+// this is skipped if the fields are never iterated.
+type KeysAndValues []any
+
+var _ field.AbstractFields = KeysAndValues(nil)
+
+// Len implements field.AbstractFields.
+func (s KeysAndValues) Len() int {
+	return (len(s) + 1) / 2
+}
+
+// ForEachField implements field.AbstractFields.
+func (s KeysAndValues) ForEachField(callback func(f *field.Field) bool) bool {
+	for idx := 0; idx < len(s); idx += 2 {
+		f := field.Field{
+			Key:   keyString(s[idx]),
+			Value: MissingValue,
+		}
+		if idx+1 < len(s) {
+			f.Value = s[idx+1]
+			if marshaler, ok := f.Value.(logr.Marshaler); ok {
+				f.Value = marshaler.MarshalLog()
+			}
+		}
+		if !callback(&f) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasErrors implements field.ErrorsDetector.
+//
+// Values implementing logr.Marshaler are not marshaled, they are
+// assumed to possibly contain an error.
+func (s KeysAndValues) HasErrors() bool {
+	for idx := 1; idx < len(s); idx += 2 {
+		switch s[idx].(type) {
+		case error, logr.Marshaler:
+			return true
+		}
+	}
+	return false
+}
+
+func keyString(key any) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	return fmt.Sprint(key)
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logr
+
+import (
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/go-logr/logr"
+)
+
+var levelMapping types.LevelMapping[int]
+
+// RegisterLevel sets the logr's verbosity (V-level) to be used for a custom level
+// (see types.RegisterLevel). Custom levels as severe as types.LevelError (or more)
+// are always logged through logr.Logger.Error instead.
+func RegisterLevel(level types.Level, verbosity int) {
+	levelMapping.Set(level, verbosity)
+}
+
+// IsErrorLevel returns true if entries of the given level should be
+// logged through logr.Logger.Error.
+func IsErrorLevel(level types.Level) bool {
+	return level > types.LevelNone && level <= types.LevelError
+}
+
+// VerbosityFromLevel converts logger.Level to logr's verbosity (V-level).
+//
+// Levels Warning and Info are mapped to V(0), Debug to V(1) and Trace to V(2).
+func VerbosityFromLevel(level types.Level) int {
+	switch level {
+	case types.LevelTrace:
+		return 2
+	case types.LevelDebug:
+		return 1
+	case types.LevelInfo, types.LevelWarning:
+		return 0
+	}
+	if verbosity, ok := levelMapping.To(level); ok {
+		return verbosity
+	}
+	if builtin := level.Builtin(); builtin != level && builtin > types.LevelNone {
+		return VerbosityFromLevel(builtin)
+	}
+	return 0
+}
+
+// LevelFromVerbosity converts logr's verbosity (V-level) to logger.Level.
+//
+// V(0) is mapped to Info, V(1) to Debug and all the higher ones to Trace.
+func LevelFromVerbosity(verbosity int) types.Level {
+	switch {
+	case verbosity <= 0:
+		return types.LevelInfo
+	case verbosity == 1:
+		return types.LevelDebug
+	}
+	if level, ok := levelMapping.From(verbosity); ok {
+		return level
+	}
+	return types.LevelTrace
+}
+
+// LevelFromLogger returns the most verbose level enabled in the logr.Logger.
+func LevelFromLogger(logrLogger logr.Logger) types.Level {
+	for _, level := range []types.Level{types.LevelTrace, types.LevelDebug, types.LevelInfo} {
+		if logrLogger.V(VerbosityFromLevel(level)).Enabled() {
+			return level
+		}
+	}
+	return types.LevelError
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logr
+
+import (
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/go-logr/logr"
+)
+
+var (
+	// FieldNameError is the key used to log the error passed to logr.Logger.Error.
+	FieldNameError = "error"
+)
+
+func init() {
+	// LogSink is called from logr, so logr's functions should not be reported as callers.
+	runtime.SkipCallerPackages("github.com/go-logr/logr")
+}
+
+// LogSink is an implementation of logr.LogSink, which sends the entries to
+// a go-belt Logger. It allows to pass a Logger (together with its context
+// fields, hooks and Emitters) to code which accepts a logr.Logger, for
+// example to Kubernetes' client-go or controller-runtime:
+//
+//	ctrl.SetLogger(beltlogr.NewLogr(logger.FromCtx(ctx)))
+//
+// V-levels are mapped to levels using LevelFromVerbosity, names (see logr.Logger.WithName)
+// are added as prefixes to messages and keys and values are logged as fields.
+type LogSink struct {
+	// Logger is the Logger to send entries to. It does not include
+	// the message prefix defined by Name.
+	Logger types.Logger
+
+	// Name is the name of the logr.Logger (see logr.Logger.WithName).
+	Name string
+
+	// named is Logger with the message prefix defined by Name.
+	named types.Logger
+}
+
+var _ logr.LogSink = (*LogSink)(nil)
+
+// NewLogSink returns a new instance of LogSink.
+func NewLogSink(logger types.Logger) *LogSink {
+	return &LogSink{
+		Logger: logger,
+		named:  logger,
+	}
+}
+
+// NewLogr returns a new logr.Logger which sends the entries to the given Logger.
+func NewLogr(logger types.Logger) logr.Logger {
+	return logr.New(NewLogSink(logger))
+}
+
+func (s *LogSink) logger() types.Logger {
+	if s.named != nil {
+		return s.named
+	}
+	return s.withName(s.Logger, s.Name)
+}
+
+func (s *LogSink) withName(logger types.Logger, name string) types.Logger {
+	if name == "" {
+		return logger
+	}
+	return logger.WithMessagePrefix(name + ": ")
+}
+
+// Init implements logr.LogSink.
+//
+// The call depth is ignored: the caller is detected by the Logger
+// (which skips logr's functions).
+func (s *LogSink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink.
+func (s *LogSink) Enabled(verbosity int) bool {
+	return s.Logger.Level() >= LevelFromVerbosity(verbosity)
+}
+
+// Info implements logr.LogSink.
+func (s *LogSink) Info(verbosity int, msg string, keysAndValues ...any) {
+	s.logger().LogFields(LevelFromVerbosity(verbosity), msg, KeysAndValues(keysAndValues))
+}
+
+// Error implements logr.LogSink.
+func (s *LogSink) Error(err error, msg string, keysAndValues ...any) {
+	var fields field.AbstractFields = KeysAndValues(keysAndValues)
+	if err != nil {
+		fields = field.Slice[field.AbstractFields]{
+			&field.Field{Key: FieldNameError, Value: err},
+			fields,
+		}
+	}
+	s.logger().LogFields(types.LevelError, msg, fields)
+}
+
+// WithValues implements logr.LogSink.
+func (s *LogSink) WithValues(keysAndValues ...any) logr.LogSink {
+	logger := s.Logger.WithFields(append(KeysAndValues(nil), keysAndValues...))
+	return &LogSink{
+		Logger: logger,
+		Name:   s.Name,
+		named:  s.withName(logger, s.Name),
+	}
+}
+
+// WithName implements logr.LogSink.
+//
+// Names are joined with "/", as recommended by logr.
+func (s *LogSink) WithName(name string) logr.LogSink {
+	if s.Name != "" {
+		name = s.Name + "/" + name
+	}
+	return &LogSink{
+		Logger: s.Logger,
+		Name:   name,
+		named:  s.withName(s.Logger, name),
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmitter struct {
+	entries []types.Entry
+	fields  []map[string]any
+}
+
+func (e *recordingEmitter) Flush() {}
+
+func (e *recordingEmitter) Emit(entry *types.Entry) {
+	fields := map[string]any{}
+	if entry.Fields != nil {
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			fields[f.Key] = f.Value
+			return true
+		})
+	}
+	e.entries = append(e.entries, *entry)
+	e.fields = append(e.fields, fields)
+}
+
+type secret string
+
+func (secret) MarshalLog() any {
+	return "***"
+}
+
+func TestLogger(t *testing.T) {
+	var lines []string
+	logrLogger := funcr.NewJSON(func(obj string) {
+		lines = append(lines, obj)
+	}, funcr.Options{Verbosity: 1})
+
+	l := New(logrLogger)
+	require.Equal(t, types.LevelDebug, l.Level())
+
+	l.InfoFields("info", field.Fields{{Key: "user_id", Value: 123}})
+	l.Debug("debug")
+	l.Trace("trace")
+	l.Error("error")
+	require.Equal(t, []string{
+		`{"logger":"","level":0,"msg":"info","user_id":123}`,
+		`{"logger":"","level":1,"msg":"debug"}`,
+		`{"logger":"","msg":"error","error":null}`,
+	}, lines)
+}
+
+func TestLogSink(t *testing.T) {
+	emitter := &recordingEmitter{}
+	l := adapter.LoggerFromEmitter(emitter).WithLevel(types.LevelDebug)
+
+	logrLogger := NewLogr(l).WithName("controller").WithValues("namespace", "default").WithName("pod")
+	require.True(t, logrLogger.V(1).Enabled())
+	require.False(t, logrLogger.V(2).Enabled())
+
+	err := errors.New("unable to reconcile")
+	logrLogger.Info("reconciling", "name", "nginx", "token", secret("qwerty"), "odd")
+	logrLogger.V(1).Info("details")
+	logrLogger.V(2).Info("ignored")
+	logrLogger.Error(err, "failed")
+
+	require.Len(t, emitter.entries, 3)
+	require.Equal(t, types.LevelInfo, emitter.entries[0].Level)
+	require.Equal(t, "controller/pod: reconciling", emitter.entries[0].Message)
+	require.Equal(t, map[string]any{
+		"namespace": "default",
+		"name":      "nginx",
+		"token":     "***",
+		"odd":       MissingValue,
+	}, emitter.fields[0])
+
+	require.Equal(t, types.LevelDebug, emitter.entries[1].Level)
+	require.Equal(t, "controller/pod: details", emitter.entries[1].Message)
+
+	require.Equal(t, types.LevelError, emitter.entries[2].Level)
+	require.Equal(t, "controller/pod: failed", emitter.entries[2].Message)
+	require.Equal(t, "default", emitter.fields[2]["namespace"])
+	require.Contains(t, emitter.fields[2], FieldNameError)
+}
+
+func TestLevelMapping(t *testing.T) {
+	for _, level := range []types.Level{types.LevelInfo, types.LevelDebug, types.LevelTrace} {
+		require.Equal(t, level, LevelFromVerbosity(VerbosityFromLevel(level)))
+	}
+	require.Equal(t, 0, VerbosityFromLevel(types.LevelWarning))
+	require.True(t, IsErrorLevel(types.LevelError))
+	require.True(t, IsErrorLevel(types.LevelPanic))
+	require.False(t, IsErrorLevel(types.LevelWarning))
+	require.False(t, IsErrorLevel(types.LevelNone))
+}
@@ -19,6 +19,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var levelMapping types.LevelMapping[logrus.Level]
+
+// RegisterLevel sets logrus's logging level to be used for a custom level
+// (see types.RegisterLevel). It should not be a panic or a fatal level.
+func RegisterLevel(level types.Level, logrusLevel logrus.Level) {
+	levelMapping.Set(level, logrusLevel)
+}
+
 // LevelToLogrus maps types.Level into logrus.Level
 func LevelToLogrus(level types.Level) logrus.Level {
 	switch level {
@@ -37,6 +45,12 @@ func LevelToLogrus(level types.Level) logrus.Level {
 	case types.LevelFatal:
 		return logrus.FatalLevel
 	}
+	if logrusLevel, ok := levelMapping.To(level); ok {
+		return logrusLevel
+	}
+	if builtin := level.Builtin(); builtin != level && builtin > types.LevelNone {
+		return LevelToLogrus(builtin)
+	}
 	panic(fmt.Errorf("unexpected level: %v", level))
 }
 
@@ -58,5 +72,8 @@ func LevelFromLogrus(level logrus.Level) types.Level {
 	case logrus.FatalLevel:
 		return types.LevelFatal
 	}
+	if customLevel, ok := levelMapping.From(level); ok {
+		return customLevel
+	}
 	panic(fmt.Errorf("unexpected level: %v", level))
 }
@@ -48,6 +48,9 @@ type Emitter struct {
 	//
 	// We use logrus.Entry instead of logrus.Logger to be able to store precompiled fields
 	LogrusEntry *logrus.Entry
+
+	// LevelVar (if set) defines the logging level instead of LogrusEntry.Level.
+	LevelVar *types.LevelVar
 }
 
 var _ types.Emitter = (*Emitter)(nil)
@@ -55,9 +58,17 @@ var _ types.Emitter = (*Emitter)(nil)
 // Flush implements types.Emitter
 func (l *Emitter) Flush() {}
 
+// Level returns the current logging level.
+func (l *Emitter) Level() types.Level {
+	if l.LevelVar != nil {
+		return l.LevelVar.Level()
+	}
+	return LevelFromLogrus(l.getLogrusEntry().Level)
+}
+
 // CheckLevel returns true if an event of a given logging level will be logged
 func (l *Emitter) CheckLevel(level types.Level) bool {
-	return LevelFromLogrus(l.getLogrusEntry().Level) >= level
+	return l.Level() >= level
 }
 
 // Emit implements types.Emitter
@@ -106,7 +117,7 @@ func (l *Emitter) logEntry(entry *types.Entry) {
 			}
 			if entry.Fields != nil {
 				entry.Fields.ForEachField(func(f *field.Field) bool {
-					fields[f.Key] = f.Value
+					fields[f.Key] = field.ResolveValue(f.Value)
 					return true
 				})
 			}
@@ -256,7 +267,7 @@ func (l *CompactLogger) WithMessagePrefix(prefix string) adapter.CompactLogger {
 // WithEntryProperties implements types.CompactLogger.
 func (l *CompactLogger) WithEntryProperties(props ...types.EntryProperty) adapter.CompactLogger {
 	clone := l.clone()
-	clone.entryProperties = clone.entryProperties.Add(props)
+	clone.entryProperties = clone.entryProperties.Add(props...)
 	return clone
 }
 
@@ -267,6 +278,7 @@ func (l *CompactLogger) LogFields(level types.Level, message string, fields fiel
 		return
 	}
 
+	fields = field.ExpandErrors(fields)
 	if preHooksResult.ExtraFields != nil {
 		fields = field.Slice[field.AbstractFields]{fields, preHooksResult.ExtraFields}
 	}
@@ -328,10 +340,13 @@ func (l *CompactLogger) Log(level types.Level, values ...any) {
 
 	var finalFields field.AbstractFields
 	valuesParser := valuesparser.AnySlice(values)
+	// the fields are parsed right away (and not when the logrus entry is compiled),
+	// because parsing also excludes structured values from valuesParser.WriteUnparsed
+	parsedFields := field.Gather(&valuesParser)
 	if preHooksResult.ExtraFields != nil {
-		finalFields = field.Slice[field.AbstractFields]{&valuesParser, preHooksResult.ExtraFields}
+		finalFields = field.Slice[field.AbstractFields]{parsedFields, preHooksResult.ExtraFields}
 	} else {
-		finalFields = &valuesParser
+		finalFields = parsedFields
 	}
 
 	logger := l
@@ -353,20 +368,23 @@ func (l *CompactLogger) Log(level types.Level, values ...any) {
 
 // Level implements types.CompactLogger
 func (l *CompactLogger) Level() types.Level {
-	return LevelFromLogrus(l.emitter.LogrusEntry.Level)
+	return l.emitter.Level()
 }
 
 // WithLevel implements types.CompactLogger
+//
+// The resulting logger is detached from the LevelVar (if it was bound to one).
 func (l *CompactLogger) WithLevel(newLevel types.Level) adapter.CompactLogger {
 	clone := l.clone()
 	clone.emitter.LogrusEntry = newLogrusEntry(l.emitter.LogrusEntry.Logger, LevelToLogrus(newLevel))
+	clone.emitter.LevelVar = nil
 	return clone
 }
 
 func (l *CompactLogger) branch() *CompactLogger {
 	return &CompactLogger{
 		mostlyPersistentData: l.mostlyPersistentData,
-		emitter:              &Emitter{LogrusEntry: l.emitter.getLogrusEntry()},
+		emitter:              &Emitter{LogrusEntry: l.emitter.getLogrusEntry(), LevelVar: l.emitter.LevelVar},
 		contextFields:        l.contextFields,
 	}
 }
@@ -430,10 +448,12 @@ func (l *CompactLogger) WithContextFields(allFields *field.FieldsChain, newField
 
 func (l *CompactLogger) compileEmitterFields() {
 	m := make(logrus.Fields, l.contextFields.Len())
-	l.contextFields.ForEachField(func(f *field.Field) bool {
-		m[f.Key] = f.Value
-		return true
-	})
+	if fields := l.hooks.ProcessContextFields(l.contextFields); fields != nil {
+		fields.ForEachField(func(f *field.Field) bool {
+			m[f.Key] = field.ResolveValue(f.Value)
+			return true
+		})
+	}
 	old := l.emitter.LogrusEntry
 	l.emitter.setLogrusEntry(&logrus.Entry{
 		Logger:  old.Logger,
@@ -460,8 +480,10 @@ func (l *CompactLogger) Emitter() types.Emitter {
 
 func newCompactLoggerFromLogrus(logrusLogger *logrus.Logger, level logger.Level, opts ...types.Option) *CompactLogger {
 	cfg := types.Options(opts).Config()
+	emitter := NewEmitter(logrusLogger, level)
+	emitter.LevelVar = cfg.LevelVar
 	return &CompactLogger{
-		emitter: NewEmitter(logrusLogger, level),
+		emitter: emitter,
 		mostlyPersistentData: &mostlyPersistentData{
 			getCallerFunc: cfg.GetCallerFunc,
 			fmtBufPool: &sync.Pool{
@@ -0,0 +1,101 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logstore
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	// DefaultSegmentSize is the default size (in bytes) of a segment,
+	// after reaching which a new segment is started.
+	DefaultSegmentSize int64 = 4 * 1024 * 1024
+
+	// DefaultMaxSize is the default maximal total size (in bytes) of the store.
+	DefaultMaxSize int64 = 256 * 1024 * 1024
+)
+
+// Option is an optional argument to function Open.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		SegmentSize: DefaultSegmentSize,
+		MaxSize:     DefaultMaxSize,
+		ErrorHandler: func(err error) {
+			fmt.Fprintf(os.Stderr, "logstore: %v\n", err)
+		},
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	SegmentSize  int64
+	MaxSize      int64
+	MaxAge       time.Duration
+	ReadOnly     bool
+	ErrorHandler func(error)
+}
+
+// OptionSegmentSize defines the size (in bytes) of a segment, after
+// reaching which a new segment is started (see DefaultSegmentSize).
+//
+// Retention works with whole segments, so it is also the granularity of the retention.
+type OptionSegmentSize int64
+
+func (opt OptionSegmentSize) apply(cfg *config) {
+	cfg.SegmentSize = int64(opt)
+}
+
+// OptionMaxSize defines the maximal total size (in bytes) of the store,
+// the oldest segments are removed to satisfy it (see DefaultMaxSize).
+// Zero value disables the limit.
+type OptionMaxSize int64
+
+func (opt OptionMaxSize) apply(cfg *config) {
+	cfg.MaxSize = int64(opt)
+}
+
+// OptionMaxAge defines the maximal age of entries, segments containing
+// only older entries are removed. Zero value (the default) disables the limit.
+type OptionMaxAge time.Duration
+
+func (opt OptionMaxAge) apply(cfg *config) {
+	cfg.MaxAge = time.Duration(opt)
+}
+
+// OptionReadOnly opens the store only to query it (for example, while
+// another process writes to it). Emit is a no-op for a read-only store.
+type OptionReadOnly bool
+
+func (opt OptionReadOnly) apply(cfg *config) {
+	cfg.ReadOnly = bool(opt)
+}
+
+// OptionErrorHandler defines the function called on errors which could
+// not be returned (for example on a failed write in Emit).
+// By default errors are printed to stderr.
+type OptionErrorHandler func(error)
+
+func (opt OptionErrorHandler) apply(cfg *config) {
+	cfg.ErrorHandler = opt
+}
@@ -0,0 +1,177 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Query defines which entries should be fetched from the Store.
+//
+// An entry should satisfy all the defined conditions. The conditions
+// on timestamp, level and trace ID are resolved using the index, while
+// Match requires reading and parsing the records.
+type Query struct {
+	// Since (if defined) skips entries with a timestamp before it.
+	Since time.Time
+
+	// Until (if defined) skips entries with a timestamp equal to or after it.
+	Until time.Time
+
+	// Level (if defined) skips entries less severe than it.
+	Level types.Level
+
+	// TraceID (if defined) skips entries without this trace ID.
+	TraceID belt.TraceID
+
+	// Match (if defined) skips entries for which it returns false.
+	Match func(entry *types.Entry) bool
+
+	// Limit (if positive) limits the amount of returned entries.
+	Limit int
+}
+
+// FieldEquals returns a function (to be used as Query.Match) which is satisfied
+// if the entry has the field with the same string representation as the given value.
+func FieldEquals(key field.Key, value field.Value) func(entry *types.Entry) bool {
+	expected := fmt.Sprint(value)
+	return func(entry *types.Entry) bool {
+		if entry.Fields == nil {
+			return false
+		}
+		found := false
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			if f.Key == key && fmt.Sprint(f.Value) == expected {
+				found = true
+				return false
+			}
+			return true
+		})
+		return found
+	}
+}
+
+type candidates struct {
+	Path    string
+	Records []indexRecord
+}
+
+func (q *Query) matchIndex(rec *indexRecord) bool {
+	return types.MatchLevelAndTime(rec.Level, time.Unix(0, rec.Timestamp), q.Level, q.Since, q.Until)
+}
+
+func (q *Query) matchSegment(seg *segment) bool {
+	if len(seg.Records) == 0 {
+		return false
+	}
+	if !q.Since.IsZero() && seg.MaxTS < q.Since.UnixNano() {
+		return false
+	}
+	if !q.Until.IsZero() && seg.MinTS >= q.Until.UnixNano() {
+		return false
+	}
+	return true
+}
+
+func (s *Store) candidates(q *Query) []candidates {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+
+	var result []candidates
+	for _, seg := range s.segments {
+		if !q.matchSegment(seg) {
+			continue
+		}
+		c := candidates{Path: seg.Path()}
+		if q.TraceID != "" {
+			for _, idx := range seg.ByTrace[q.TraceID] {
+				if q.matchIndex(&seg.Records[idx]) {
+					c.Records = append(c.Records, seg.Records[idx])
+				}
+			}
+		} else {
+			for idx := range seg.Records {
+				if q.matchIndex(&seg.Records[idx]) {
+					c.Records = append(c.Records, seg.Records[idx])
+				}
+			}
+		}
+		if len(c.Records) > 0 {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Query calls the callback for each entry satisfying the Query (in the order
+// the entries were stored), until the callback returns false.
+func (s *Store) Query(q Query, callback func(entry *types.Entry) bool) error {
+	count := 0
+	for _, c := range s.candidates(&q) {
+		stop, err := func() (bool, error) {
+			f, err := os.Open(c.Path)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					// the segment was removed by the retention
+					return false, nil
+				}
+				return false, err
+			}
+			defer f.Close()
+
+			var buf []byte
+			for _, rec := range c.Records {
+				if int64(cap(buf)) < rec.Length {
+					buf = make([]byte, rec.Length)
+				}
+				buf = buf[:rec.Length]
+				if _, err := f.ReadAt(buf, rec.Offset); err != nil {
+					return false, fmt.Errorf("unable to read a record from '%s' at %d: %w", c.Path, rec.Offset, err)
+				}
+				entry, err := UnmarshalEntry(buf)
+				if err != nil {
+					return false, fmt.Errorf("unable to parse a record from '%s' at %d: %w", c.Path, rec.Offset, err)
+				}
+				if q.Match != nil && !q.Match(entry) {
+					continue
+				}
+				count++
+				if !callback(entry) || (q.Limit > 0 && count >= q.Limit) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}()
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find returns all the entries satisfying the Query.
+func (s *Store) Find(q Query) ([]*types.Entry, error) {
+	var result []*types.Entry
+	err := s.Query(q, func(entry *types.Entry) bool {
+		result = append(result, entry)
+		return true
+	})
+	return result, err
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/replay"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Keys of the predefined members of a stored record (a JSON object);
+// all other members are fields of the entry.
+const (
+	KeyTimestamp = "ts"
+	KeyLevel     = "level"
+	KeyMessage   = "msg"
+	KeyTraceIDs  = "trace_id"
+)
+
+// MarshalEntry returns the entry encoded the same way as it is stored:
+// as a single-line JSON object, which could be read by replay.Reader.
+func MarshalEntry(entry *types.Entry) []byte {
+	buf := make([]byte, 0, 256)
+	buf = append(buf, `{"`+KeyTimestamp+`":`...)
+	buf = appendJSON(buf, entry.Timestamp.Format(time.RFC3339Nano))
+	buf = append(buf, `,"`+KeyLevel+`":`...)
+	buf = appendJSON(buf, entry.Level.String())
+	buf = append(buf, `,"`+KeyMessage+`":`...)
+	buf = appendJSON(buf, entry.Message)
+	if len(entry.TraceIDs) > 0 {
+		buf = append(buf, `,"`+KeyTraceIDs+`":`...)
+		buf = appendJSON(buf, entry.TraceIDs)
+	}
+	if entry.Fields != nil {
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			buf = append(buf, ',')
+			buf = appendJSON(buf, f.Key)
+			buf = append(buf, ':')
+			buf = appendJSON(buf, fieldValue(f.Value))
+			return true
+		})
+	}
+	return append(buf, '}')
+}
+
+// UnmarshalEntry parses a record encoded by MarshalEntry.
+func UnmarshalEntry(record []byte) (*types.Entry, error) {
+	return replay.NewReader(bytes.NewReader(record)).Read()
+}
+
+func fieldValue(value field.Value) any {
+	value = field.ResolveValue(value)
+	switch value := value.(type) {
+	case error:
+		return value.Error()
+	case json.Marshaler:
+		return value
+	case fmt.Stringer:
+		return value.String()
+	}
+	return value
+}
+
+func appendJSON(buf []byte, value any) []byte {
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return append(buf, b...)
+}
@@ -0,0 +1,220 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+const (
+	segmentExt = ".log"
+	indexExt   = ".idx"
+)
+
+// indexRecord is the index of a single stored entry.
+type indexRecord struct {
+	Offset    int64         `json:"o"`
+	Length    int64         `json:"n"`
+	Timestamp int64         `json:"t"`
+	Level     types.Level   `json:"l"`
+	TraceIDs  belt.TraceIDs `json:"tr,omitempty"`
+}
+
+// segment is an in-memory index of a segment: a pair of files, where
+// the ".log" file contains the records (one JSON object per line) and
+// the ".idx" file contains the index records (also as JSON objects).
+type segment struct {
+	Dir     string
+	Seq     uint64
+	Size    int64
+	MinTS   int64
+	MaxTS   int64
+	Records []indexRecord
+	ByTrace map[belt.TraceID][]int
+}
+
+func newSegment(dir string, seq uint64) *segment {
+	return &segment{
+		Dir:     dir,
+		Seq:     seq,
+		ByTrace: map[belt.TraceID][]int{},
+	}
+}
+
+func (s *segment) Path() string {
+	return filepath.Join(s.Dir, fmt.Sprintf("%020d%s", s.Seq, segmentExt))
+}
+
+func (s *segment) IndexPath() string {
+	return filepath.Join(s.Dir, fmt.Sprintf("%020d%s", s.Seq, indexExt))
+}
+
+func (s *segment) Add(rec indexRecord) {
+	if len(s.Records) == 0 || rec.Timestamp < s.MinTS {
+		s.MinTS = rec.Timestamp
+	}
+	if len(s.Records) == 0 || rec.Timestamp > s.MaxTS {
+		s.MaxTS = rec.Timestamp
+	}
+	for _, traceID := range rec.TraceIDs {
+		s.ByTrace[traceID] = append(s.ByTrace[traceID], len(s.Records))
+	}
+	s.Records = append(s.Records, rec)
+	if end := rec.Offset + rec.Length; end > s.Size {
+		s.Size = end
+	}
+}
+
+// listSegments returns sequence numbers of the segments in the directory in ascending order.
+func listSegments(dir string) ([]uint64, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var result []uint64
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		result = append(result, seq)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// loadSegment loads the index of the segment, or rebuilds it from the records
+// if the index file is missing or "rebuild" is true.
+func loadSegment(dir string, seq uint64, rebuild bool) (*segment, error) {
+	s := newSegment(dir, seq)
+	if !rebuild {
+		err := s.loadIndex()
+		switch {
+		case err == nil:
+			return s, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+	if err := s.buildIndex(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *segment) loadIndex() error {
+	f, err := os.Open(s.IndexPath())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(f)
+	for {
+		var rec indexRecord
+		err := decoder.Decode(&rec)
+		switch {
+		case err == nil:
+			s.Add(rec)
+		case errors.Is(err, io.EOF):
+			return nil
+		default:
+			return fmt.Errorf("unable to parse index '%s': %w", s.IndexPath(), err)
+		}
+	}
+}
+
+// buildIndex builds the index by scanning the records. A trailing incomplete
+// record (for example, left by a crash) is ignored and will be overwritten.
+func (s *segment) buildIndex() error {
+	f, err := os.Open(s.Path())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		rec := indexRecord{Offset: offset, Length: int64(len(line))}
+		offset += int64(len(line))
+		entry, parseErr := UnmarshalEntry(line)
+		if parseErr != nil {
+			// a corrupted record, but we still keep the space occupied by it
+			s.Size = offset
+			continue
+		}
+		rec.Timestamp = entry.Timestamp.UnixNano()
+		rec.Level = entry.Level
+		rec.TraceIDs = entry.TraceIDs
+		s.Add(rec)
+	}
+}
+
+// writeIndex rewrites the index file of the segment.
+func (s *segment) writeIndex() error {
+	f, err := os.Create(s.IndexPath())
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, rec := range s.Records {
+		if err := encoder.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *segment) Remove() error {
+	if err := os.Remove(s.Path()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(s.IndexPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *segment) MaxTime() time.Time {
+	return time.Unix(0, s.MaxTS)
+}
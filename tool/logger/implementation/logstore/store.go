@@ -0,0 +1,244 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package logstore implements an embedded queryable on-disk log store.
+//
+// It is designed for devices without a log pipeline: Store is a types.Emitter,
+// which appends entries to segment files, maintains a small index on
+// timestamp, level and trace IDs, applies retention by size and age, and
+// allows to query entries back (see Store.Query and command cmd/logstore).
+package logstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Store is an on-disk segmented log store.
+//
+// Records are stored as JSON objects (one per line, see MarshalEntry), so
+// segment files could also be read by any tool supporting NDJSON logs.
+type Store struct {
+	dir    string
+	config config
+
+	locker      sync.RWMutex
+	segments    []*segment
+	active      *os.File
+	activeIndex *os.File
+	closed      bool
+}
+
+var _ types.Emitter = (*Store)(nil)
+
+// Open opens (or creates) the store in the given directory.
+func Open(dir string, opts ...Option) (*Store, error) {
+	cfg := options(opts).Config()
+	if !cfg.ReadOnly {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return nil, fmt.Errorf("unable to create directory '%s': %w", dir, err)
+		}
+	}
+
+	seqs, err := listSegments(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list segments in '%s': %w", dir, err)
+	}
+
+	s := &Store{
+		dir:    dir,
+		config: cfg,
+	}
+	for idx, seq := range seqs {
+		// the index of the last segment may be incomplete, rebuilding it
+		isLast := idx == len(seqs)-1
+		seg, err := loadSegment(dir, seq, isLast)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load segment %d: %w", seq, err)
+		}
+		s.segments = append(s.segments, seg)
+	}
+	if cfg.ReadOnly {
+		return s, nil
+	}
+
+	if len(s.segments) == 0 {
+		s.segments = append(s.segments, newSegment(dir, 1))
+	} else {
+		last := s.segments[len(s.segments)-1]
+		if err := os.Truncate(last.Path(), last.Size); err != nil {
+			return nil, fmt.Errorf("unable to truncate an incomplete record of segment %d: %w", last.Seq, err)
+		}
+		if err := last.writeIndex(); err != nil {
+			return nil, fmt.Errorf("unable to write the index of segment %d: %w", last.Seq, err)
+		}
+	}
+	if err := s.openActive(); err != nil {
+		return nil, err
+	}
+	s.applyRetention()
+	return s, nil
+}
+
+// New returns a types.Logger which writes to the Store.
+func New(store *Store, opts ...types.Option) types.Logger {
+	return adapter.LoggerFromEmitter(store, opts...)
+}
+
+func (s *Store) lastSegment() *segment {
+	return s.segments[len(s.segments)-1]
+}
+
+func (s *Store) openActive() error {
+	seg := s.lastSegment()
+	active, err := os.OpenFile(seg.Path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return fmt.Errorf("unable to open segment '%s': %w", seg.Path(), err)
+	}
+	activeIndex, err := os.OpenFile(seg.IndexPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		active.Close()
+		return fmt.Errorf("unable to open index '%s': %w", seg.IndexPath(), err)
+	}
+	s.active, s.activeIndex = active, activeIndex
+	return nil
+}
+
+// Emit implements types.Emitter.
+func (s *Store) Emit(entry *types.Entry) {
+	if s.config.ReadOnly {
+		return
+	}
+	record := append(MarshalEntry(entry), '\n')
+
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if s.closed {
+		return
+	}
+
+	seg := s.lastSegment()
+	if seg.Size > 0 && seg.Size+int64(len(record)) > s.config.SegmentSize {
+		if err := s.rotate(); err != nil {
+			s.config.ErrorHandler(err)
+			return
+		}
+		seg = s.lastSegment()
+	}
+
+	if _, err := s.active.Write(record); err != nil {
+		s.config.ErrorHandler(fmt.Errorf("unable to write to segment '%s': %w", seg.Path(), err))
+		return
+	}
+	rec := indexRecord{
+		Offset:    seg.Size,
+		Length:    int64(len(record)),
+		Timestamp: entry.Timestamp.UnixNano(),
+		Level:     entry.Level,
+		TraceIDs:  entry.TraceIDs,
+	}
+	seg.Add(rec)
+
+	indexLine, err := json.Marshal(rec)
+	if err != nil {
+		s.config.ErrorHandler(err)
+		return
+	}
+	if _, err := s.activeIndex.Write(append(indexLine, '\n')); err != nil {
+		s.config.ErrorHandler(fmt.Errorf("unable to write to index '%s': %w", seg.IndexPath(), err))
+	}
+}
+
+// rotate closes the active segment and starts a new one.
+func (s *Store) rotate() error {
+	if err := s.closeActive(); err != nil {
+		return err
+	}
+	s.segments = append(s.segments, newSegment(s.dir, s.lastSegment().Seq+1))
+	if err := s.openActive(); err != nil {
+		return err
+	}
+	s.applyRetention()
+	return nil
+}
+
+// applyRetention removes the oldest segments (except the active one) which
+// violate the limits of size and age.
+func (s *Store) applyRetention() {
+	var totalSize int64
+	for _, seg := range s.segments {
+		totalSize += seg.Size
+	}
+	var minTime time.Time
+	if s.config.MaxAge > 0 {
+		minTime = time.Now().Add(-s.config.MaxAge)
+	}
+
+	for len(s.segments) > 1 {
+		oldest := s.segments[0]
+		tooBig := s.config.MaxSize > 0 && totalSize > s.config.MaxSize
+		tooOld := !minTime.IsZero() && oldest.MaxTime().Before(minTime)
+		if !tooBig && !tooOld {
+			break
+		}
+		if err := oldest.Remove(); err != nil {
+			s.config.ErrorHandler(fmt.Errorf("unable to remove segment %d: %w", oldest.Seq, err))
+			break
+		}
+		totalSize -= oldest.Size
+		s.segments = s.segments[1:]
+	}
+}
+
+func (s *Store) closeActive() error {
+	if s.active == nil {
+		return nil
+	}
+	err := s.active.Sync()
+	if closeErr := s.active.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := s.activeIndex.Close(); err == nil {
+		err = closeErr
+	}
+	s.active, s.activeIndex = nil, nil
+	return err
+}
+
+// Flush implements types.Emitter. It syncs the active segment to the disk.
+func (s *Store) Flush() {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if s.active == nil {
+		return
+	}
+	if err := s.active.Sync(); err != nil {
+		s.config.ErrorHandler(fmt.Errorf("unable to sync segment: %w", err))
+	}
+	if err := s.activeIndex.Sync(); err != nil {
+		s.config.ErrorHandler(fmt.Errorf("unable to sync index: %w", err))
+	}
+}
+
+// Close flushes and closes the Store. Entries emitted after that are dropped.
+func (s *Store) Close() error {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.closed = true
+	return s.closeActive()
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logstore
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testEntry(ts time.Time, level types.Level, message string, traceID belt.TraceID, fields ...field.Field) *types.Entry {
+	entry := &types.Entry{
+		Timestamp: ts,
+		Level:     level,
+		Message:   message,
+		Fields:    field.Fields(fields),
+	}
+	if traceID != "" {
+		entry.TraceIDs = belt.TraceIDs{traceID}
+	}
+	return entry
+}
+
+func messages(entries []*types.Entry) []string {
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.Message)
+	}
+	return result
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	require.NoError(t, err)
+
+	ts := time.Date(2022, 2, 24, 1, 0, 0, 0, time.UTC)
+	store.Emit(testEntry(ts, types.LevelInfo, "one", "t1", field.Field{Key: "user_id", Value: 42}))
+	store.Emit(testEntry(ts.Add(time.Second), types.LevelError, "two", "t2"))
+	store.Emit(testEntry(ts.Add(2*time.Second), types.LevelWarning, "three", "t1", field.Field{Key: "user_id", Value: 7}))
+
+	entries, err := store.Find(Query{TraceID: "t1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "three"}, messages(entries))
+	require.Equal(t, belt.TraceIDs{"t1"}, entries[0].TraceIDs)
+	require.True(t, ts.Equal(entries[0].Timestamp))
+	require.Equal(t, field.Fields{{Key: "user_id", Value: int64(42)}}, entries[0].Fields)
+
+	entries, err = store.Find(Query{Level: types.LevelWarning})
+	require.NoError(t, err)
+	require.Equal(t, []string{"two", "three"}, messages(entries))
+
+	entries, err = store.Find(Query{Since: ts.Add(time.Second), Until: ts.Add(2 * time.Second)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"two"}, messages(entries))
+
+	entries, err = store.Find(Query{Match: FieldEquals("user_id", 7)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"three"}, messages(entries))
+
+	entries, err = store.Find(Query{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one"}, messages(entries))
+
+	require.NoError(t, store.Close())
+
+	// simulating a crash in the middle of writing a record
+	f, err := os.OpenFile(store.lastSegment().Path(), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"ts":"2022-02-24T01:00:03Z","lev`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	store, err = Open(dir)
+	require.NoError(t, err)
+	defer store.Close()
+	store.Emit(testEntry(ts.Add(4*time.Second), types.LevelInfo, "four", "t1"))
+	entries, err = store.Find(Query{TraceID: "t1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "three", "four"}, messages(entries))
+
+	readOnly, err := Open(dir, OptionReadOnly(true))
+	require.NoError(t, err)
+	entries, err = readOnly.Find(Query{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two", "three", "four"}, messages(entries))
+}
+
+func TestStoreRetention(t *testing.T) {
+	dir := t.TempDir()
+	var errs []error
+	store, err := Open(dir,
+		OptionSegmentSize(200),
+		OptionMaxSize(500),
+		OptionErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+	require.NoError(t, err)
+	defer store.Close()
+
+	ts := time.Now()
+	for i := 0; i < 20; i++ {
+		store.Emit(testEntry(ts.Add(time.Duration(i)*time.Second), types.LevelInfo, "message", ""))
+	}
+	require.Empty(t, errs)
+
+	seqs, err := listSegments(dir)
+	require.NoError(t, err)
+	require.Greater(t, len(seqs), 1)
+	require.Equal(t, len(store.segments), len(seqs))
+	var totalSize int64
+	for _, seg := range store.segments {
+		totalSize += seg.Size
+	}
+	require.LessOrEqual(t, totalSize, int64(500+200))
+
+	entries, err := store.Find(Query{})
+	require.NoError(t, err)
+	require.Less(t, len(entries), 20)
+	require.True(t, ts.Add(19*time.Second).Equal(entries[len(entries)-1].Timestamp))
+}
+
+func TestStoreMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir, OptionSegmentSize(1), OptionMaxAge(time.Hour))
+	require.NoError(t, err)
+	defer store.Close()
+
+	store.Emit(testEntry(time.Now().Add(-2*time.Hour), types.LevelInfo, "old", ""))
+	store.Emit(testEntry(time.Now(), types.LevelInfo, "new0", ""))
+	store.Emit(testEntry(time.Now(), types.LevelInfo, "new1", ""))
+
+	entries, err := store.Find(Query{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"new0", "new1"}, messages(entries))
+}
@@ -38,6 +38,13 @@ var Default = func() types.Logger {
 }
 
 // New returns a Logger using given a logger of the standard package "log".
+//
+// If the Logger is bound to a LevelVar (see types.OptionLevelVar), then
+// argument "level" is ignored.
 func New(stdLogger *log.Logger, level types.Level, opts ...types.Option) types.Logger {
-	return adapter.LoggerFromPrintfer(stdLogger, opts...).WithLevel(level)
+	l := adapter.LoggerFromPrintfer(stdLogger, opts...)
+	if types.Options(opts).Config().LevelVar != nil {
+		return l
+	}
+	return l.WithLevel(level)
 }
@@ -0,0 +1,118 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package testlogger provides a Logger which writes to a testing.TB,
+// so that the log is shown (by `go test -v` or on a failure) together
+// with the test which produced it.
+package testlogger
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Emitter is a types.Emitter implementation which writes entries
+// through testing.TB.Log.
+//
+// After the test (including its subtests) is completed, entries are
+// silently dropped, because testing.TB panics if Log is called after that.
+type Emitter struct {
+	TB        testing.TB
+	FailLevel types.Level
+
+	locker    sync.RWMutex
+	completed bool
+}
+
+var _ types.Emitter = (*Emitter)(nil)
+
+// NewEmitter returns a new instance of Emitter bound to the given test.
+func NewEmitter(tb testing.TB, opts ...Option) *Emitter {
+	cfg := options(opts).Config()
+	emitter := &Emitter{
+		TB:        tb,
+		FailLevel: cfg.FailLevel,
+	}
+	tb.Cleanup(emitter.stop)
+	return emitter
+}
+
+// New returns a new instance of types.Logger bound to the given test.
+func New(tb testing.TB, opts ...Option) types.Logger {
+	cfg := options(opts).Config()
+	l := adapter.LoggerFromEmitter(NewEmitter(tb, opts...), cfg.LoggerOptions...)
+	if types.Options(cfg.LoggerOptions).Config().LevelVar != nil {
+		return l
+	}
+	return l.WithLevel(cfg.Level)
+}
+
+func (e *Emitter) stop() {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	e.completed = true
+}
+
+// Flush implements types.Emitter.
+func (*Emitter) Flush() {}
+
+// Emit implements types.Emitter.
+func (e *Emitter) Emit(entry *types.Entry) {
+	e.locker.RLock()
+	defer e.locker.RUnlock()
+	if e.completed {
+		return
+	}
+
+	msg := formatEntry(entry)
+	if e.FailLevel > types.LevelNone && entry.Level > types.LevelNone && entry.Level <= e.FailLevel {
+		e.TB.Error(msg)
+		return
+	}
+	e.TB.Log(msg)
+}
+
+func formatEntry(entry *types.Entry) string {
+	var result strings.Builder
+	result.WriteByte('[')
+	result.WriteByte(entry.Level.Byte())
+	if file, line := entry.Caller.FileLine(); line != 0 {
+		result.WriteByte(' ')
+		result.WriteString(filepath.Base(file))
+		result.WriteByte(':')
+		result.WriteString(strconv.Itoa(line))
+	}
+	result.WriteString("] ")
+	result.WriteString(entry.Message)
+
+	if entry.Fields != nil {
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			result.WriteByte(' ')
+			result.WriteString(f.Key)
+			result.WriteByte('=')
+			fmt.Fprintf(&result, "%v", f.Value)
+			return true
+		})
+	}
+	if len(entry.TraceIDs) > 0 {
+		fmt.Fprintf(&result, " trace_id=%v", entry.TraceIDs)
+	}
+	return result.String()
+}
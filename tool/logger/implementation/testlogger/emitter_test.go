@@ -0,0 +1,98 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package testlogger
+
+import (
+	"fmt"
+	stdruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTB struct {
+	testing.TB
+	Logs     []string
+	IsFailed bool
+	Cleanups []func()
+}
+
+func (tb *fakeTB) Log(args ...any) {
+	tb.Logs = append(tb.Logs, fmt.Sprint(args...))
+}
+
+func (tb *fakeTB) Error(args ...any) {
+	tb.Log(args...)
+	tb.IsFailed = true
+}
+
+func (tb *fakeTB) Cleanup(fn func()) {
+	tb.Cleanups = append(tb.Cleanups, fn)
+}
+
+func (tb *fakeTB) complete() {
+	for _, fn := range tb.Cleanups {
+		fn()
+	}
+}
+
+// getCallerPC is the same as the default one, but does not skip tests of this package.
+func getCallerPC() runtime.PC {
+	return runtime.Caller(func(pc uintptr) bool {
+		return strings.Contains(stdruntime.FuncForPC(pc).Name(), "testlogger.Test")
+	})
+}
+
+func TestLogger(t *testing.T) {
+	tb := &fakeTB{}
+	l := New(tb,
+		OptionLevel(types.LevelInfo),
+		OptionFailLevel(types.LevelError),
+		OptionLoggerOptions{types.OptionGetCallerFunc(getCallerPC)},
+	)
+
+	l.Debug("skipped")
+	l.WithField("user_id", 1).Info("hello")
+	require.Len(t, tb.Logs, 1)
+	require.Regexp(t, `^\[I emitter_test\.go:\d+\] hello user_id=1$`, tb.Logs[0])
+	require.False(t, tb.IsFailed)
+
+	l.Warn("careful")
+	require.False(t, tb.IsFailed)
+	l.Error("oops")
+	require.True(t, tb.IsFailed)
+	require.Len(t, tb.Logs, 3)
+
+	tb.complete()
+	l.Info("after the test")
+	require.Len(t, tb.Logs, 3)
+}
+
+func TestLoggerRealTB(t *testing.T) {
+	l := New(t)
+	l.Debugf("a message from %s", t.Name())
+
+	done := make(chan struct{})
+	t.Run("subtest", func(t *testing.T) {
+		l := New(t)
+		go func() {
+			<-done
+			l.Info("the subtest is already completed")
+		}()
+		l.Info("inside the subtest")
+	})
+	close(done)
+}
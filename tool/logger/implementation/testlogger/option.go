@@ -0,0 +1,68 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package testlogger
+
+import (
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// Option is an optional argument to functions New and NewEmitter.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		Level:     types.LevelTrace,
+		FailLevel: types.LevelNone,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	Level         types.Level
+	FailLevel     types.Level
+	LoggerOptions []types.Option
+}
+
+// OptionLevel defines the logging level of the Logger returned by New.
+//
+// The default value is types.LevelTrace (log everything).
+type OptionLevel types.Level
+
+func (opt OptionLevel) apply(cfg *config) {
+	cfg.Level = types.Level(opt)
+}
+
+// OptionFailLevel makes the test fail if an entry of the given level
+// or a more severe one is emitted. For example OptionFailLevel(types.LevelError)
+// fails the test on Error, Panic and Fatal entries.
+//
+// The default value is types.LevelNone (never fail the test).
+type OptionFailLevel types.Level
+
+func (opt OptionFailLevel) apply(cfg *config) {
+	cfg.FailLevel = types.Level(opt)
+}
+
+// OptionLoggerOptions passes options to the Logger returned by New.
+type OptionLoggerOptions []types.Option
+
+func (opt OptionLoggerOptions) apply(cfg *config) {
+	cfg.LoggerOptions = opt
+}
@@ -0,0 +1,268 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package zap
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	// FieldNameLoggerName is the field name used to store the name of a zap logger
+	// (see zap.Logger.Named) in entries sent through Core.
+	FieldNameLoggerName = "logger"
+
+	// FieldNameStacktrace is the field name used to store the stack trace
+	// collected by zap (see zap.AddStacktrace) in entries sent through Core.
+	FieldNameStacktrace = "stacktrace"
+)
+
+func init() {
+	// Core is called from zap, so zap's functions should not be reported as callers.
+	runtime.SkipCallerPackages("go.uber.org/zap")
+}
+
+// Core is an implementation of zapcore.Core, which sends the entries to
+// a go-belt Logger. It allows to pass a Logger (together with its context
+// fields, hooks and Emitters) to code which accepts a *zap.Logger:
+//
+//	import (
+//		"go.uber.org/zap"
+//		beltzap "github.com/facebookincubator/go-belt/tool/logger/implementation/zap"
+//	)
+//
+//	zapLogger := zap.New(beltzap.NewCore(logger.FromCtx(ctx)))
+//
+// zap fields are converted to field.Field-s lazily, only if an entry
+// is actually going to be processed (for example passed to a Hook or an Emitter).
+//
+// Levels Panic and Fatal are handled by the Logger (which panics or exits
+// after logging the entry).
+type Core struct {
+	Logger types.Logger
+
+	// namespace is the prefix of keys of fields, set by zap.Namespace.
+	namespace string
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+// NewCore returns a new instance of Core.
+func NewCore(logger types.Logger) *Core {
+	return &Core{
+		Logger: logger,
+	}
+}
+
+// NewCoreFromEmitter returns a new instance of Core, which sends entries
+// of the given level (and more severe) directly to the Emitter.
+func NewCoreFromEmitter(emitter types.Emitter, level types.Level) *Core {
+	return NewCore(adapter.LoggerFromEmitter(emitter).WithLevel(level))
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.Logger.Level() >= LevelFromZap(level)
+}
+
+// With implements zapcore.Core.
+func (c *Core) With(zapFields []zapcore.Field) zapcore.Core {
+	if len(zapFields) == 0 {
+		return c
+	}
+	fields := &Fields{
+		Namespace: c.namespace,
+		ZapFields: append([]zapcore.Field(nil), zapFields...),
+	}
+	return &Core{
+		Logger:    c.Logger.WithFields(fields),
+		namespace: fields.namespaceAfter(),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(entry zapcore.Entry, checkedEntry *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checkedEntry
+	}
+	return checkedEntry.AddCore(entry, c)
+}
+
+// Write implements zapcore.Core.
+func (c *Core) Write(entry zapcore.Entry, zapFields []zapcore.Field) error {
+	var fields field.AbstractFields = &Fields{
+		Namespace: c.namespace,
+		ZapFields: zapFields,
+	}
+	if entry.LoggerName != "" || entry.Stack != "" {
+		var extraFields field.Fields
+		if entry.LoggerName != "" {
+			extraFields = append(extraFields, field.Field{Key: FieldNameLoggerName, Value: entry.LoggerName})
+		}
+		if entry.Stack != "" {
+			extraFields = append(extraFields, field.Field{Key: FieldNameStacktrace, Value: entry.Stack})
+		}
+		fields = field.Slice[field.AbstractFields]{extraFields, fields}
+	}
+	c.Logger.LogFields(LevelFromZap(entry.Level), entry.Message, fields)
+	return nil
+}
+
+// Sync implements zapcore.Core.
+func (c *Core) Sync() error {
+	c.Logger.Flush(context.Background())
+	return nil
+}
+
+// Fields is an implementation of field.AbstractFields based on zap fields.
+//
+// The fields are converted on each iteration, so the conversion is
+// skipped if the fields are never iterated.
+type Fields struct {
+	// Namespace is the prefix of keys, see zap.Namespace.
+	Namespace string
+
+	ZapFields []zapcore.Field
+}
+
+var (
+	_ field.AbstractFields = (*Fields)(nil)
+	_ field.ErrorsDetector = (*Fields)(nil)
+)
+
+// Len implements field.AbstractFields.
+//
+// It is only a hint: a zap field may result into multiple fields
+// (for example zap.Inline) or into none (for example zap.Skip).
+func (fields *Fields) Len() int {
+	return len(fields.ZapFields)
+}
+
+// HasErrors implements field.ErrorsDetector.
+func (fields *Fields) HasErrors() bool {
+	for idx := range fields.ZapFields {
+		if fields.ZapFields[idx].Type == zapcore.ErrorType {
+			return true
+		}
+	}
+	return false
+}
+
+// ForEachField implements field.AbstractFields.
+func (fields *Fields) ForEachField(callback func(f *field.Field) bool) bool {
+	namespace := fields.Namespace
+	for idx := range fields.ZapFields {
+		zapField := &fields.ZapFields[idx]
+		switch zapField.Type {
+		case zapcore.SkipType:
+			continue
+		case zapcore.NamespaceType:
+			namespace += zapField.Key + "."
+			continue
+		}
+
+		if value, ok := zapFieldValue(zapField); ok {
+			if !callback(&field.Field{Key: namespace + zapField.Key, Value: value}) {
+				return false
+			}
+			continue
+		}
+
+		// Complex values are converted through zap's own marshaling.
+		enc := zapcore.NewMapObjectEncoder()
+		zapField.AddTo(enc)
+		keys := make([]string, 0, len(enc.Fields))
+		for key := range enc.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !callback(&field.Field{Key: namespace + key, Value: enc.Fields[key]}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// namespaceAfter returns the namespace to be used for fields following these ones.
+func (fields *Fields) namespaceAfter() string {
+	namespace := fields.Namespace
+	for idx := range fields.ZapFields {
+		if zapField := &fields.ZapFields[idx]; zapField.Type == zapcore.NamespaceType {
+			namespace += zapField.Key + "."
+		}
+	}
+	return namespace
+}
+
+// zapFieldValue returns the value of a zap field of a simple type,
+// or false if the field should be marshaled by zap.
+func zapFieldValue(zapField *zap.Field) (any, bool) {
+	switch zapField.Type {
+	case zapcore.StringType:
+		return zapField.String, true
+	case zapcore.BoolType:
+		return zapField.Integer == 1, true
+	case zapcore.Int64Type:
+		return zapField.Integer, true
+	case zapcore.Int32Type:
+		return int32(zapField.Integer), true
+	case zapcore.Int16Type:
+		return int16(zapField.Integer), true
+	case zapcore.Int8Type:
+		return int8(zapField.Integer), true
+	case zapcore.Uint64Type:
+		return uint64(zapField.Integer), true
+	case zapcore.Uint32Type:
+		return uint32(zapField.Integer), true
+	case zapcore.Uint16Type:
+		return uint16(zapField.Integer), true
+	case zapcore.Uint8Type:
+		return uint8(zapField.Integer), true
+	case zapcore.UintptrType:
+		return uintptr(zapField.Integer), true
+	case zapcore.Float64Type:
+		return math.Float64frombits(uint64(zapField.Integer)), true
+	case zapcore.Float32Type:
+		return math.Float32frombits(uint32(zapField.Integer)), true
+	case zapcore.DurationType:
+		return time.Duration(zapField.Integer), true
+	case zapcore.TimeType:
+		ts := time.Unix(0, zapField.Integer)
+		if loc, ok := zapField.Interface.(*time.Location); ok {
+			ts = ts.In(loc)
+		}
+		return ts, true
+	case zapcore.TimeFullType:
+		return zapField.Interface, true
+	case zapcore.ByteStringType:
+		return string(zapField.Interface.([]byte)), true
+	case zapcore.BinaryType, zapcore.Complex128Type, zapcore.Complex64Type, zapcore.ReflectType:
+		return zapField.Interface, true
+	case zapcore.ErrorType:
+		if err, ok := zapField.Interface.(error); ok && err != nil {
+			return err, true
+		}
+	}
+	return nil, false
+}
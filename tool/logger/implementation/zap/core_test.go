@@ -0,0 +1,120 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package zap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type recordingEmitter struct {
+	entries []types.Entry
+	fields  []map[string]any
+}
+
+func (e *recordingEmitter) Flush() {}
+
+func (e *recordingEmitter) Emit(entry *types.Entry) {
+	fields := map[string]any{}
+	if entry.Fields != nil {
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			fields[f.Key] = f.Value
+			return true
+		})
+	}
+	e.entries = append(e.entries, *entry)
+	e.fields = append(e.fields, fields)
+}
+
+type dropHook struct{}
+
+func (dropHook) ProcessLogEntry(*types.Entry) bool { return false }
+func (dropHook) Flush()                            {}
+
+func TestCore(t *testing.T) {
+	emitter := &recordingEmitter{}
+	zapLogger := zap.New(NewCoreFromEmitter(emitter, types.LevelInfo)).
+		Named("svc").
+		With(zap.String("service", "billing"), zap.Namespace("req"))
+
+	err := errors.New("unable to charge")
+	zapLogger.Debug("ignored")
+	zapLogger.Warn("charging",
+		zap.Int("attempt", 2),
+		zap.Bool("retry", true),
+		zap.Duration("elapsed", time.Second),
+		zap.Object("user", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddInt("id", 123)
+			return nil
+		})),
+		zap.Error(err),
+		zap.Skip(),
+	)
+
+	if len(emitter.entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(emitter.entries))
+	}
+	entry := emitter.entries[0]
+	if entry.Level != types.LevelWarning || entry.Message != "charging" {
+		t.Fatalf("unexpected entry: %#+v", entry)
+	}
+	// This test is a part of go-belt, so it is skipped as well; the point is
+	// to skip zap.
+	if file, _ := entry.Caller.FileLine(); !entry.Caller.Defined() || strings.Contains(file, "go.uber.org/zap") {
+		t.Fatalf("unexpected caller file: %s", file)
+	}
+
+	expected := map[string]any{
+		"logger":      "svc",
+		"service":     "billing",
+		"req.attempt": int64(2),
+		"req.retry":   true,
+		"req.elapsed": time.Second,
+		"req.user":    map[string]any{"id": 123},
+		"req.error":   err,
+	}
+	fields := emitter.fields[0]
+	for key, value := range expected {
+		requireString(t, fmt.Sprint(value), fmt.Sprint(fields[key]))
+	}
+	if _, ok := fields["req.service"]; ok {
+		t.Fatalf("the namespace should not affect previous fields: %#+v", fields)
+	}
+}
+
+func TestCoreLazyFields(t *testing.T) {
+	emitter := &recordingEmitter{}
+	logger := adapter.LoggerFromEmitter(emitter).WithLevel(types.LevelTrace).WithHooks(dropHook{})
+	zapLogger := zap.New(NewCore(logger))
+
+	marshaled := false
+	zapLogger.Info("test", zap.Object("value", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		marshaled = true
+		return nil
+	})))
+	if marshaled {
+		t.Fatal("the field was converted for a dropped entry")
+	}
+	if len(emitter.entries) != 0 {
+		t.Fatalf("expected no entries, got %d", len(emitter.entries))
+	}
+}
@@ -20,6 +20,14 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+var levelMapping types.LevelMapping[zapcore.Level]
+
+// RegisterLevel sets zap's logging level to be used for a custom level
+// (see types.RegisterLevel). It should not be a panic or a fatal level.
+func RegisterLevel(level types.Level, zapLevel zapcore.Level) {
+	levelMapping.Set(level, zapLevel)
+}
+
 // LevelToZap converts logger.Level to zap's logging level.
 func LevelToZap(level types.Level) zapcore.Level {
 	switch level {
@@ -38,6 +46,12 @@ func LevelToZap(level types.Level) zapcore.Level {
 	case types.LevelFatal:
 		return zap.FatalLevel
 	}
+	if zapLevel, ok := levelMapping.To(level); ok {
+		return zapLevel
+	}
+	if builtin := level.Builtin(); builtin != level && builtin > types.LevelNone {
+		return LevelToZap(builtin)
+	}
 	panic(fmt.Errorf("unexpected level: %v", level))
 }
 
@@ -54,10 +68,17 @@ func LevelFromZap(level zapcore.Level) types.Level {
 		return types.LevelWarning
 	case zap.ErrorLevel:
 		return types.LevelError
+	case zap.DPanicLevel:
+		// zap panics on DPanic only in development mode, so it is
+		// considered as just an error here.
+		return types.LevelError
 	case zap.PanicLevel:
 		return types.LevelPanic
 	case zap.FatalLevel:
 		return types.LevelFatal
 	}
+	if customLevel, ok := levelMapping.From(level); ok {
+		return customLevel
+	}
 	panic(fmt.Errorf("unexpected level: %v", level))
 }
@@ -167,9 +167,15 @@ type mostlyPersistentData struct {
 type CompactLogger struct {
 	*mostlyPersistentData
 	emitter            Emitter
+	levelVar           *types.LevelVar
 	contextFields      *field.FieldsChain
 	contextNewFields   uint32
 	prepareEmitterOnce sync.Once
+	// level is the logging level set by WithLevel; if defined, it overrides
+	// the level of the zap logger (in both directions).
+	level types.Level
+	// rootZapLogger is the zap logger without the compiled context fields.
+	rootZapLogger *zap.Logger
 }
 
 var _ adapter.CompactLogger = (*CompactLogger)(nil)
@@ -253,19 +259,33 @@ func (l *CompactLogger) logEntry(entry *logger.Entry) {
 	zapEntry.Message = entry.Message
 	zapEntry.Level = LevelToZap(entry.Level)
 	var zapFields []zap.Field
-	zapFieldsPtr := fieldsToZap(entry.Fields.Len(), entry.Fields.ForEachField)
-	if zapFieldsPtr != nil {
-		defer releaseZapFields(zapFieldsPtr)
-		zapFields = *zapFieldsPtr
+	if entry.Fields != nil {
+		zapFieldsPtr := fieldsToZap(entry.Fields.Len(), entry.Fields.ForEachField)
+		if zapFieldsPtr != nil {
+			defer releaseZapFields(zapFieldsPtr)
+			zapFields = *zapFieldsPtr
+		}
 	}
 
-	l.logZapEntryNoHooks(zapEntry, zapFields...)
+	// entry.Fields already contain the context fields (processed by the hooks),
+	// so the zap logger without the compiled context fields is used.
+	l.writeZapEntry(Emitter{ZapLogger: l.rootZapLogger}, zapEntry, zapFields...)
 }
 
 func (l *CompactLogger) logZapEntryNoHooks(zapEntry *zapcore.Entry, zapFields ...zapcore.Field) {
 	l.prepareEmitter()
+	l.writeZapEntry(l.emitter, zapEntry, zapFields...)
+}
+
+func (l *CompactLogger) writeZapEntry(emitter Emitter, zapEntry *zapcore.Entry, zapFields ...zapcore.Field) {
 	l.zapSetCaller(zapEntry)
-	l.emitter.LogZapEntry(*zapEntry, zapFields...)
+	if l.level != types.LevelUndefined && !emitter.CheckZapLevel(zapEntry.Level) {
+		// The level was lowered by WithLevel below the level of the zap
+		// logger, so bypassing its level check.
+		_ = emitter.getZapLogger().Core().Write(*zapEntry, zapFields)
+	} else {
+		emitter.LogZapEntry(*zapEntry, zapFields...)
+	}
 
 	// zap authors decided not to panic or/and exit on Panics and Fatals,
 	// see: https://github.com/uber-go/zap/issues/358
@@ -300,11 +320,12 @@ func (l *CompactLogger) zapSetCaller(zapEntry *zapcore.Entry) {
 
 // LogFields implements types.CompactLogger.
 func (l *CompactLogger) LogFields(level types.Level, message string, fields field.AbstractFields) {
-	preHooksResult := adapter.LogFieldsPreprocess(l.preHooks, l.traceIDs, level, l.emitter.CheckLevel(level), message, fields)
+	preHooksResult := adapter.LogFieldsPreprocess(l.preHooks, l.traceIDs, level, l.checkLevel(level), message, fields)
 	if preHooksResult.Skip {
 		return
 	}
 
+	fields = field.ExpandErrors(fields)
 	if preHooksResult.ExtraFields != nil {
 		fields = field.Slice[field.AbstractFields]{fields, preHooksResult.ExtraFields}
 	}
@@ -315,7 +336,7 @@ func (l *CompactLogger) LogFields(level types.Level, message string, fields fiel
 
 		entry.Level = level
 		entry.Message = l.messagePrefix + message
-		entry.Fields = fields
+		entry.Fields = field.Add(entry.Fields, fields)
 		l.logEntry(entry)
 		return
 	}
@@ -340,7 +361,7 @@ func (l *CompactLogger) LogFields(level types.Level, message string, fields fiel
 
 // Logf implements types.CompactLogger.
 func (l *CompactLogger) Logf(level types.Level, format string, args ...any) {
-	preHooksResult := adapter.LogfPreprocess(l.preHooks, l.traceIDs, level, l.emitter.CheckLevel(level), format, args...)
+	preHooksResult := adapter.LogfPreprocess(l.preHooks, l.traceIDs, level, l.checkLevel(level), format, args...)
 	if preHooksResult.Skip {
 		return
 	}
@@ -358,7 +379,7 @@ func (l *CompactLogger) Logf(level types.Level, format string, args ...any) {
 
 		entry.Level = level
 		entry.Message = buf.String()
-		entry.Fields = preHooksResult.ExtraFields
+		entry.Fields = field.Add(entry.Fields, preHooksResult.ExtraFields)
 		l.logEntry(entry)
 		return
 	}
@@ -385,13 +406,17 @@ func (l *CompactLogger) Logf(level types.Level, format string, args ...any) {
 func (l *CompactLogger) Log(level types.Level, values ...any) {
 	forceProcess := level == logger.LevelFatal || level == logger.LevelPanic
 
-	preHooksResult := adapter.LogPreprocess(l.preHooks, l.traceIDs, level, l.emitter.CheckLevel(level), values...)
+	preHooksResult := adapter.LogPreprocess(l.preHooks, l.traceIDs, level, l.checkLevel(level), values...)
 	if preHooksResult.Skip && !forceProcess {
 		return
 	}
 
 	if len(values) == 1 {
 		if entry, ok := values[0].(*logger.Entry); ok {
+			// logEntry does not use the compiled context fields
+			if l.contextFields != nil {
+				entry.Fields = field.Add(l.contextFields, entry.Fields)
+			}
 			if entry.TraceIDs != nil {
 				entry.Fields = field.Add(entry.Fields, preHooksResult.ExtraFields, &field.Field{Key: FieldNameTraceIDs, Value: entry.TraceIDs})
 			} else {
@@ -409,11 +434,11 @@ func (l *CompactLogger) Log(level types.Level, values ...any) {
 		defer l.releaseEntry(entry)
 
 		entry.Level = level
-		entry.Fields = field.Add(entry.Fields, preHooksResult.ExtraFields)
+		// parsing the fields also makes valuesParser.WriteUnparsed(buf) work correctly
+		entry.Fields = field.Add(entry.Fields, field.Gather(&valuesParser), preHooksResult.ExtraFields)
 
 		buf := l.acquireBuf()
 		defer l.releaseBuf(buf)
-		valuesParser.ForEachField(func(f *field.Field) bool { return true }) // just causing to parse the fields to make valuesParser.WriteUnparsed(buf) work correctly
 		buf.WriteString(l.messagePrefix)
 		valuesParser.WriteUnparsed(buf)
 		entry.Message = buf.String()
@@ -451,11 +476,30 @@ func (l *CompactLogger) Log(level types.Level, values ...any) {
 	l.logZapEntryNoHooks(entry, zapFields...)
 }
 
+func (l *CompactLogger) checkLevel(level types.Level) bool {
+	if l.level != types.LevelUndefined {
+		return level <= l.level
+	}
+	if l.levelVar != nil && l.levelVar.Level() < level {
+		return false
+	}
+	return l.emitter.CheckLevel(level)
+}
+
 // Level implements types.CompactLogger.
 func (l *CompactLogger) Level() types.Level {
+	if l.level != types.LevelUndefined {
+		return l.level
+	}
+	maxLevel := types.EndOfLevel - 1
+	if l.levelVar != nil {
+		maxLevel = l.levelVar.Level()
+	}
 	core := l.emitter.ZapLogger.Core()
-	for level := types.EndOfLevel - 1; level >= 0; level-- {
-		if core.Enabled(LevelToZap(level)) {
+	levels := types.Levels()
+	for idx := len(levels) - 1; idx >= 0; idx-- {
+		level := levels[idx]
+		if level <= maxLevel && core.Enabled(LevelToZap(level)) {
 			return level
 		}
 	}
@@ -463,9 +507,14 @@ func (l *CompactLogger) Level() types.Level {
 }
 
 // WithLevel implements types.CompactLogger.
+//
+// The level overrides the level of the zap logger, it could be both
+// higher and lower. The resulting logger is detached from the LevelVar
+// (if it was bound to one).
 func (l *CompactLogger) WithLevel(newLevel types.Level) adapter.CompactLogger {
 	branch := l.branch()
-	branch.emitter.ZapLogger = branch.emitter.ZapLogger.WithOptions(zap.IncreaseLevel(LevelToZap(newLevel)))
+	branch.levelVar = nil
+	branch.level = newLevel
 	return branch
 }
 
@@ -473,8 +522,11 @@ func (l *CompactLogger) branch() *CompactLogger {
 	return &CompactLogger{
 		mostlyPersistentData: l.mostlyPersistentData,
 		emitter:              Emitter{ZapLogger: l.emitter.getZapLogger()},
+		levelVar:             l.levelVar,
+		level:                l.level,
 		contextFields:        l.contextFields,
 		contextNewFields:     atomic.LoadUint32(&l.contextNewFields),
+		rootZapLogger:        l.rootZapLogger,
 	}
 }
 
@@ -494,7 +546,7 @@ func (l *CompactLogger) WithMessagePrefix(prefix string) adapter.CompactLogger {
 // WithEntryProperties implements types.CompactLogger.
 func (l *CompactLogger) WithEntryProperties(props ...types.EntryProperty) adapter.CompactLogger {
 	clone := l.clone()
-	clone.entryProperties = clone.entryProperties.Add(props)
+	clone.entryProperties = clone.entryProperties.Add(props...)
 	return clone
 }
 
@@ -504,6 +556,12 @@ func (l *CompactLogger) WithHooks(hooks ...types.Hook) adapter.CompactLogger {
 	clone.hooks = make(types.Hooks, len(l.hooks)+len(hooks))
 	copy(clone.hooks, l.hooks)
 	copy(clone.hooks[len(l.hooks):], hooks)
+	if types.Hooks(hooks).HasContextFieldsHook() {
+		// the already compiled context fields have not been processed
+		// by the new hooks, so compiling all of them again:
+		clone.emitter = Emitter{ZapLogger: clone.rootZapLogger}
+		clone.contextNewFields = uint32(clone.contextFields.Len())
+	}
 	return clone
 }
 
@@ -564,7 +622,24 @@ func (l *CompactLogger) compileEmitterFields() {
 		return
 	}
 	defer atomic.StoreUint32(&l.contextNewFields, 0)
-	zapFieldsPtr := fieldsToZap(int(l.contextNewFields), l.contextFields.ForEachField)
+
+	// FieldsChain provides the newest fields first, and only
+	// the fields which are not compiled yet are needed. They are
+	// added in the reversed order, so that the newest value of
+	// a duplicated key is the last one.
+	newFields := make(field.Fields, 0, l.contextNewFields)
+	l.contextFields.ForEachField(func(f *field.Field) bool {
+		newFields = append(newFields, *f)
+		return len(newFields) < int(l.contextNewFields)
+	})
+	for i, j := 0, len(newFields)-1; i < j; i, j = i+1, j-1 {
+		newFields[i], newFields[j] = newFields[j], newFields[i]
+	}
+	fields := l.hooks.ProcessContextFields(newFields)
+	if fields == nil {
+		return
+	}
+	zapFieldsPtr := fieldsToZap(fields.Len(), fields.ForEachField)
 	if zapFieldsPtr == nil {
 		return
 	}
@@ -614,7 +689,9 @@ func New(logger *zap.Logger, opts ...types.Option) types.Logger {
 func newCompactLoggerFromZap(zapLogger *zap.Logger, opts ...types.Option) *CompactLogger {
 	cfg := types.Options(opts).Config()
 	return &CompactLogger{
-		emitter: NewEmitter(zapLogger),
+		emitter:       NewEmitter(zapLogger),
+		levelVar:      cfg.LevelVar,
+		rootZapLogger: zapLogger,
 		mostlyPersistentData: &mostlyPersistentData{
 			getCallerFunc: cfg.GetCallerFunc,
 			fmtBufPool: &sync.Pool{
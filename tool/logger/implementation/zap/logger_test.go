@@ -14,14 +14,19 @@ package zap
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger"
 	"github.com/facebookincubator/go-belt/tool/logger/types"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type buffer struct {
@@ -68,6 +73,169 @@ func TestLogger(t *testing.T) {
 	buf.Reset()
 }
 
+func TestLoggerLazyValue(t *testing.T) {
+	var buf buffer
+	zapLogger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
+		&buf,
+		zap.InfoLevel,
+	))
+
+	calls := 0
+	lazy := field.Lazy(func() any {
+		calls++
+		return "expensive"
+	})
+	l := New(zapLogger, types.OptionGetCallerFunc(nil)).WithField("lazy", lazy)
+	l.Debug("test")
+	requireString(t, "", buf.String())
+	if calls != 0 {
+		t.Fatalf("the lazy value was computed for a filtered out entry")
+	}
+
+	l.Info("test")
+	l.Info("test")
+	if !strings.Contains(buf.String(), `"lazy":"expensive"`) {
+		t.Fatalf("the lazy value was not logged: '%s'", buf.String())
+	}
+	if calls != 1 {
+		t.Fatalf("the lazy value was computed %d times", calls)
+	}
+}
+
+type keysRecorderHook struct {
+	Keys []string
+}
+
+func (hook *keysRecorderHook) ProcessLogEntry(entry *types.Entry) bool {
+	entry.Fields.ForEachField(func(f *field.Field) bool {
+		hook.Keys = append(hook.Keys, f.Key)
+		return true
+	})
+	return true
+}
+
+func (hook *keysRecorderHook) Flush() {}
+
+func TestLoggerHookContextFields(t *testing.T) {
+	var buf buffer
+	zapLogger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
+		&buf,
+		zap.InfoLevel,
+	))
+	hook := &keysRecorderHook{}
+	l := New(zapLogger, types.OptionGetCallerFunc(nil)).WithHooks(hook).WithField("user_id", 1)
+
+	l.Info("test")
+	l.Infof("test")
+	l.InfoFields("test", field.Fields{{Key: "attempt", Value: 2}})
+	if strings.Join(hook.Keys, ",") != "user_id,user_id,user_id,attempt" {
+		t.Fatalf("unexpected keys seen by the hook: %v", hook.Keys)
+	}
+	if strings.Count(buf.String(), `"user_id":1`) != 3 {
+		t.Fatalf("unexpected output: '%s'", buf.String())
+	}
+}
+
+func TestLoggerError(t *testing.T) {
+	var buf buffer
+	zapLogger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
+		&buf,
+		zap.InfoLevel,
+	))
+
+	err := fmt.Errorf("unable to do something: %w", io.EOF)
+	l := New(zapLogger, types.OptionGetCallerFunc(nil))
+	l.Error(err)
+	l.ErrorFields("test", field.Fields{{Key: "cause", Value: err}})
+	for _, expected := range []string{
+		`"error":"unable to do something: EOF"`,
+		`"error.type":"*fmt.wrapError"`,
+		`"error.chain":["*fmt.wrapError","*errors.errorString"]`,
+		`"cause":"unable to do something: EOF"`,
+		`"cause.type":"*fmt.wrapError"`,
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("'%s' was not found in '%s'", expected, buf.String())
+		}
+	}
+}
+
+func TestLoggerCustomLevel(t *testing.T) {
+	levelNotice := types.MustRegisterLevel(types.LevelDefinition{
+		Level: types.LevelInfo - types.LevelStep/2,
+		Name:  "notice",
+	})
+	levelCritical := types.MustRegisterLevel(types.LevelDefinition{
+		Level: types.LevelError - types.LevelStep/2,
+		Name:  "critical",
+	})
+	RegisterLevel(levelCritical, zap.ErrorLevel)
+	if LevelToZap(levelNotice) != zap.InfoLevel {
+		t.Fatalf("unexpected zap level for notice: %v", LevelToZap(levelNotice))
+	}
+	if LevelFromZap(zap.ErrorLevel) != types.LevelError {
+		t.Fatalf("a built-in level is expected to be preferred, but got: %v", LevelFromZap(zap.ErrorLevel))
+	}
+
+	var buf buffer
+	zapLogger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
+		&buf,
+		zap.WarnLevel,
+	))
+	l := New(zapLogger, types.OptionGetCallerFunc(nil))
+	l.Log(levelNotice, "notice")
+	requireString(t, "", buf.String())
+	l.Log(levelCritical, "critical")
+	if !strings.Contains(buf.String(), `"L":"ERROR"`) {
+		t.Fatalf("unexpected output: '%s'", buf.String())
+	}
+	if l.Level() != types.LevelWarning {
+		t.Fatalf("unexpected level: %v", l.Level())
+	}
+}
+
+func TestLoggerCtxWithLevel(t *testing.T) {
+	var buf, errBuf buffer
+	zapLogger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
+		&buf,
+		zap.InfoLevel,
+	), zap.ErrorOutput(&errBuf))
+	ctx := logger.CtxWithLogger(context.Background(), New(zapLogger, types.OptionGetCallerFunc(nil)))
+
+	traceCtx := logger.CtxWithLevel(ctx, types.LevelTrace)
+	l := logger.FromCtx(traceCtx)
+	if l.Level() != types.LevelTrace {
+		t.Fatalf("unexpected level: %v", l.Level())
+	}
+	l.Debug("debug")
+	if !strings.Contains(buf.String(), `"M":"debug"`) {
+		t.Fatalf("unexpected output: '%s'", buf.String())
+	}
+	buf.Reset()
+
+	l = logger.FromCtx(logger.CtxWithLevel(traceCtx, types.LevelError))
+	if l.Level() != types.LevelError {
+		t.Fatalf("unexpected level: %v", l.Level())
+	}
+	l.Warn("warning")
+	requireString(t, "", buf.String())
+	l.Error("error")
+	if !strings.Contains(buf.String(), `"M":"error"`) {
+		t.Fatalf("unexpected output: '%s'", buf.String())
+	}
+	buf.Reset()
+
+	l = logger.FromCtx(ctx)
+	l.Debug("debug")
+	requireString(t, "", buf.String())
+	requireString(t, "", errBuf.String())
+}
+
 func requireString(t *testing.T, expected, actual string) {
 	if expected != actual {
 		t.Fatalf("expected string: '%s', actual: '%s'", expected, actual)
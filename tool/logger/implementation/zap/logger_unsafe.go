@@ -22,6 +22,9 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// fieldsToZap converts fields to zap fields. `length` is used only as a hint of the
+// amount of fields, a collection may provide more fields (for example an error is
+// expanded to multiple fields, see field.ForEachErrorField).
 func fieldsToZap(length int, forEachField func(callback func(f *field.Field) bool) bool) *[]zap.Field {
 	if length == 0 {
 		return nil
@@ -33,16 +36,12 @@ func fieldsToZap(length int, forEachField func(callback func(f *field.Field) boo
 	if len(*result) != 0 {
 		panic(len(*result))
 	}
-	count := 0
 	appendToResult := func(f *field.Field) bool {
-		count++
-		if count > length {
-			return false
-		}
 		zapField := zap.Field{
 			Key: f.Key,
 		}
-		switch value := f.Value.(type) {
+		fieldValue := field.ResolveValue(f.Value)
+		switch value := fieldValue.(type) {
 		case string:
 			zapField.Type = zapcore.StringType
 			zapField.String = value
@@ -54,10 +53,10 @@ func fieldsToZap(length int, forEachField func(callback func(f *field.Field) boo
 			zapField.Integer = int64(math.Float64bits(value))
 		case error:
 			zapField.Type = zapcore.ErrorType
-			zapField.Interface = f.Value
+			zapField.Interface = fieldValue
 		default:
 			zapField.Type = zapcore.ReflectType
-			zapField.Interface = f.Value
+			zapField.Interface = fieldValue
 		}
 		*result = append(*result, zapField)
 		return true
@@ -65,10 +64,6 @@ func fieldsToZap(length int, forEachField func(callback func(f *field.Field) boo
 
 	// See the assumption described in the "WARNING" message of field.ForEachFieldser.
 	forEachField(*(*func(f *field.Field) bool)(noescape(unsafe.Pointer(&appendToResult))))
-
-	if len(*result) > length {
-		panic("should not happen")
-	}
 	return result
 }
 
@@ -0,0 +1,144 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package zerolog
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/rs/zerolog"
+)
+
+type zerologConfig struct {
+	Name   string
+	Logger zerolog.Logger
+}
+
+func zerologConfigs() []zerologConfig {
+	return []zerologConfig{
+		{
+			Name:   "json",
+			Logger: zerolog.New(io.Discard),
+		},
+		{
+			Name:   "nop",
+			Logger: zerolog.Nop(),
+		},
+	}
+}
+
+func Benchmark(b *testing.B) {
+	for _, zerologConfig := range zerologConfigs() {
+		b.Run(zerologConfig.Name, func(b *testing.B) {
+
+			for depth := 0; depth <= 256; depth = 1 + depth*4/3 {
+				var keys [256][4]string
+				for i, _keys := range keys {
+					for j := range _keys {
+						keys[i][j] = fmt.Sprintf("key %d:%d", i, j)
+					}
+				}
+				b.Run(fmt.Sprintf("depth%d", depth), func(b *testing.B) {
+					b.Run("WithField", func(b *testing.B) {
+						for _, callLog := range []bool{false, true} {
+							b.Run(fmt.Sprintf("callLog-%v", callLog), func(b *testing.B) {
+								b.Run("bare_zerolog", func(b *testing.B) {
+									lOrig := zerologConfig.Logger
+									b.ReportAllocs()
+									b.ResetTimer()
+									for i := 0; i < b.N; i++ {
+										l := lOrig
+										for num := 0; num < depth; num++ {
+											l = l.With().Str(keys[num][0], "some value").Logger()
+											l = l.With().
+												Str(keys[num][1], "more values 1").
+												Str(keys[num][2], "more values 2").
+												Int(keys[num][3], 3).
+												Logger()
+										}
+										if callLog {
+											l.Error().Msg("unit-test")
+										}
+									}
+								})
+								b.Run("adapted_zerolog", func(b *testing.B) {
+									lOrig := New(zerologConfig.Logger)
+									b.ReportAllocs()
+									b.ResetTimer()
+									for i := 0; i < b.N; i++ {
+										l := lOrig
+										for num := 0; num < depth; num++ {
+											l = l.WithField(keys[num][0], "some value")
+											l = l.WithFields(field.Fields{
+												{Key: keys[num][1], Value: "more values 1"},
+												{Key: keys[num][2], Value: "more values 2"},
+												{Key: keys[num][3], Value: 3},
+											})
+										}
+										if callLog {
+											l.Errorf("unit-test")
+										}
+									}
+								})
+							})
+						}
+					})
+					b.Run("Log", func(b *testing.B) {
+						zerologLogger := zerologConfig.Logger
+						for num := 0; num < depth; num++ {
+							zerologLogger = zerologLogger.With().Str(keys[num][0], "some value").Logger()
+						}
+
+						for fieldNum := 0; fieldNum < 8; fieldNum++ {
+							b.Run(fmt.Sprintf("fields-%d", fieldNum), func(b *testing.B) {
+								b.Run("bare_zerolog", func(b *testing.B) {
+									l := zerologLogger
+									var fields []string
+									for i := 0; i < fieldNum; i++ {
+										fields = append(fields, fmt.Sprintf("key %d", i), fmt.Sprintf("value %d", i))
+									}
+									b.ReportAllocs()
+									b.ResetTimer()
+									for i := 0; i < b.N; i++ {
+										event := l.Error()
+										for idx := 0; idx < len(fields); idx += 2 {
+											event = event.Str(fields[idx], fields[idx+1])
+										}
+										event.Msg("unit-test")
+									}
+								})
+								b.Run("adapted_zerolog", func(b *testing.B) {
+									l := New(zerologLogger)
+									var fields field.Fields
+									for i := 0; i < fieldNum; i++ {
+										fields = append(fields, field.Field{
+											Key:   fmt.Sprintf("key %d", i),
+											Value: fmt.Sprintf("value %d", i),
+										})
+									}
+									b.ReportAllocs()
+									b.ResetTimer()
+									for i := 0; i < b.N; i++ {
+										l.ErrorFields("unit-test", &fields)
+									}
+								})
+							})
+						}
+					})
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package zerolog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/rs/zerolog"
+)
+
+// fieldEncoder is the set of typed encoders shared by
+// *zerolog.Event and zerolog.Context.
+type fieldEncoder[E any] interface {
+	Str(key, value string) E
+	Bool(key string, value bool) E
+	Int(key string, value int) E
+	Int8(key string, value int8) E
+	Int16(key string, value int16) E
+	Int32(key string, value int32) E
+	Int64(key string, value int64) E
+	Uint(key string, value uint) E
+	Uint8(key string, value uint8) E
+	Uint16(key string, value uint16) E
+	Uint32(key string, value uint32) E
+	Uint64(key string, value uint64) E
+	Float32(key string, value float32) E
+	Float64(key string, value float64) E
+	Time(key string, value time.Time) E
+	Dur(key string, value time.Duration) E
+	AnErr(key string, value error) E
+	Bytes(key string, value []byte) E
+	Stringer(key string, value fmt.Stringer) E
+	Interface(key string, value any) E
+}
+
+var (
+	_ fieldEncoder[*zerolog.Event]  = (*zerolog.Event)(nil)
+	_ fieldEncoder[zerolog.Context] = zerolog.Context{}
+)
+
+// encodeField adds the field to the event or context using the typed
+// encoder of the value (reflection is used only for the rest of the values).
+func encodeField[E fieldEncoder[E]](enc E, f *field.Field) E {
+	switch value := field.ResolveValue(f.Value).(type) {
+	case string:
+		return enc.Str(f.Key, value)
+	case bool:
+		return enc.Bool(f.Key, value)
+	case int:
+		return enc.Int(f.Key, value)
+	case int8:
+		return enc.Int8(f.Key, value)
+	case int16:
+		return enc.Int16(f.Key, value)
+	case int32:
+		return enc.Int32(f.Key, value)
+	case int64:
+		return enc.Int64(f.Key, value)
+	case uint:
+		return enc.Uint(f.Key, value)
+	case uint8:
+		return enc.Uint8(f.Key, value)
+	case uint16:
+		return enc.Uint16(f.Key, value)
+	case uint32:
+		return enc.Uint32(f.Key, value)
+	case uint64:
+		return enc.Uint64(f.Key, value)
+	case float32:
+		return enc.Float32(f.Key, value)
+	case float64:
+		return enc.Float64(f.Key, value)
+	case time.Time:
+		return enc.Time(f.Key, value)
+	case time.Duration:
+		return enc.Dur(f.Key, value)
+	case error:
+		return enc.AnErr(f.Key, value)
+	case []byte:
+		return enc.Bytes(f.Key, value)
+	case fmt.Stringer:
+		return enc.Stringer(f.Key, value)
+	default:
+		return enc.Interface(f.Key, value)
+	}
+}
+
+// eventEncoder holds a callback for field.ForEachFieldser, which adds
+// fields to an event. It is pooled together with the callback, so
+// that encoding fields does not allocate a closure for each entry.
+type eventEncoder struct {
+	event    *zerolog.Event
+	callback func(f *field.Field) bool
+}
+
+var eventEncoderPool = sync.Pool{
+	New: func() any {
+		enc := &eventEncoder{}
+		enc.callback = func(f *field.Field) bool {
+			enc.event = encodeField(enc.event, f)
+			return true
+		}
+		return enc
+	},
+}
+
+// encodeFields adds the fields to the event.
+func encodeFields(event *zerolog.Event, fields field.AbstractFields) *zerolog.Event {
+	if fields == nil {
+		return event
+	}
+	enc := eventEncoderPool.Get().(*eventEncoder)
+	enc.event = event
+	fields.ForEachField(enc.callback)
+	event = enc.event
+	enc.event = nil
+	eventEncoderPool.Put(enc)
+	return event
+}
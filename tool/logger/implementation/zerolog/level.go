@@ -0,0 +1,83 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package zerolog
+
+import (
+	"fmt"
+
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/rs/zerolog"
+)
+
+var levelMapping types.LevelMapping[zerolog.Level]
+
+// RegisterLevel sets zerolog's logging level to be used for a custom level
+// (see types.RegisterLevel). It should not be a panic or a fatal level.
+func RegisterLevel(level types.Level, zerologLevel zerolog.Level) {
+	levelMapping.Set(level, zerologLevel)
+}
+
+// LevelToZerolog converts logger.Level to zerolog's logging level.
+func LevelToZerolog(level types.Level) zerolog.Level {
+	switch level {
+	case types.LevelNone:
+		return zerolog.Disabled
+	case types.LevelTrace:
+		return zerolog.TraceLevel
+	case types.LevelDebug:
+		return zerolog.DebugLevel
+	case types.LevelInfo:
+		return zerolog.InfoLevel
+	case types.LevelWarning:
+		return zerolog.WarnLevel
+	case types.LevelError:
+		return zerolog.ErrorLevel
+	case types.LevelPanic:
+		return zerolog.PanicLevel
+	case types.LevelFatal:
+		return zerolog.FatalLevel
+	}
+	if zerologLevel, ok := levelMapping.To(level); ok {
+		return zerologLevel
+	}
+	if builtin := level.Builtin(); builtin != level && builtin > types.LevelNone {
+		return LevelToZerolog(builtin)
+	}
+	panic(fmt.Errorf("unexpected level: %v", level))
+}
+
+// LevelFromZerolog converts zerolog's logging level to logger.Level.
+func LevelFromZerolog(level zerolog.Level) types.Level {
+	switch level {
+	case zerolog.Disabled:
+		return types.LevelNone
+	case zerolog.TraceLevel:
+		return types.LevelTrace
+	case zerolog.DebugLevel:
+		return types.LevelDebug
+	case zerolog.InfoLevel:
+		return types.LevelInfo
+	case zerolog.WarnLevel:
+		return types.LevelWarning
+	case zerolog.ErrorLevel:
+		return types.LevelError
+	case zerolog.PanicLevel:
+		return types.LevelPanic
+	case zerolog.FatalLevel:
+		return types.LevelFatal
+	}
+	if customLevel, ok := levelMapping.From(level); ok {
+		return customLevel
+	}
+	panic(fmt.Errorf("unexpected level: %v", level))
+}
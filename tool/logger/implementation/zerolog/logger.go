@@ -0,0 +1,552 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package zerolog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/valuesparser"
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/facebookincubator/go-belt/tool/logger/adapter"
+	"github.com/facebookincubator/go-belt/tool/logger/experimental"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/rs/zerolog"
+)
+
+var (
+	// FieldNameTraceIDs is the field name used to store belt.TraceIDs.
+	FieldNameTraceIDs = "trace_id"
+)
+
+// Emitter is the implementation of types.Emitter based on a zerolog logger.
+//
+// The timestamp and the caller of an entry are added by the Emitter, so
+// the zerolog logger should not be configured to add them by itself
+// (see zerolog.Context.Timestamp and zerolog.Context.Caller).
+type Emitter struct {
+	ZerologLogger *zerolog.Logger
+}
+
+var _ types.Emitter = (*Emitter)(nil)
+
+// NewEmitter returns a new instance of Emitter
+func NewEmitter(zerologLogger zerolog.Logger) Emitter {
+	return Emitter{
+		ZerologLogger: &zerologLogger,
+	}
+}
+
+// Flush implements types.Emitter.
+//
+// zerolog writes each entry directly to the writer (it does not
+// have its own buffers), so there is nothing to flush.
+func (l Emitter) Flush() {}
+
+// CheckZerologLevel returns true if the given zerolog's logging level is enabled in this logger.
+func (l Emitter) CheckZerologLevel(level zerolog.Level) bool {
+	return l.checkZerologLevel(level)
+}
+
+func (l *Emitter) checkZerologLevel(level zerolog.Level) bool {
+	return level >= l.getZerologLogger().GetLevel() && level >= zerolog.GlobalLevel()
+}
+
+// CheckLevel returns true if the given types.Level is enabled in this logger.
+func (l Emitter) CheckLevel(level types.Level) bool {
+	if level == types.LevelNone {
+		return false
+	}
+	return l.CheckZerologLevel(LevelToZerolog(level))
+}
+
+// Emit implements types.Emitter.
+//
+// Unlike zerolog.Logger.Panic and zerolog.Logger.Fatal it never panics or exits,
+// this is done by the Logger.
+func (l Emitter) Emit(entry *types.Entry) {
+	if !l.isEnabled(entry.Level) {
+		return
+	}
+
+	zerologLogger := l.getZerologLogger()
+	zerologLevel := LevelToZerolog(entry.Level)
+	event := zerologLogger.WithLevel(zerologLevel)
+	if event == nil {
+		forcedLogger := zerologLogger.Level(zerolog.TraceLevel)
+		if event = forcedLogger.WithLevel(zerologLevel); event == nil {
+			return
+		}
+	}
+
+	if !entry.Timestamp.IsZero() {
+		event = event.Time(zerolog.TimestampFieldName, entry.Timestamp)
+	}
+	if entry.Caller.Defined() {
+		file, line := entry.Caller.FileLine()
+		event = event.Str(zerolog.CallerFieldName, zerolog.CallerMarshalFunc(uintptr(entry.Caller), file, line))
+	}
+	if !entry.Properties.Has(EntryPropertyIgnoreFields) {
+		event = encodeFields(event, entry.Fields)
+	}
+	event.Msg(entry.Message)
+}
+
+// isEnabled returns true if an entry of the given level is going to be emitted.
+func (l *Emitter) isEnabled(level types.Level) bool {
+	// We log panics and fatals even if logging level is lower because
+	// they supposed to trigger panic or os.Exit, and we cannot just return without that.
+	// So it should be silent, but destructive.
+	if level == types.LevelNone {
+		return false
+	}
+	return level <= types.LevelPanic || l.checkZerologLevel(LevelToZerolog(level))
+}
+
+func (l *Emitter) setZerologLogger(zerologLogger *zerolog.Logger) {
+	atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&l.ZerologLogger)), unsafe.Pointer(zerologLogger))
+}
+
+func (l *Emitter) getZerologLogger() *zerolog.Logger {
+	return (*zerolog.Logger)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&l.ZerologLogger))))
+}
+
+type mostlyPersistentData struct {
+	entryPool       *sync.Pool
+	fmtBufPool      *sync.Pool
+	preHooks        logger.PreHooks
+	hooks           logger.Hooks
+	traceIDs        belt.TraceIDs
+	getCallerFunc   types.GetCallerPC
+	messagePrefix   string
+	entryProperties types.EntryProperties
+}
+
+// CompactLogger is an implementation of types.CompactLogger based on a zerolog logger.
+//
+// Context fields are compiled into the zerolog logger (see zerolog.Context),
+// so they are encoded only once; and therefore Hooks receive only the fields
+// of a specific entry.
+//
+// The caller of an entry is resolved only if it is going to be used (by Hooks
+// or by the Emitter). It is the most expensive part of logging an entry,
+// so if it is not needed, use types.OptionGetCallerFunc(nil).
+type CompactLogger struct {
+	*mostlyPersistentData
+	emitter            Emitter
+	levelVar           *types.LevelVar
+	contextFields      *field.FieldsChain
+	contextNewFields   uint32
+	prepareEmitterOnce sync.Once
+	// rootZerologLogger is the zerolog logger without the compiled context fields.
+	rootZerologLogger *zerolog.Logger
+}
+
+var _ adapter.CompactLogger = (*CompactLogger)(nil)
+
+// Flush implements types.CompactLogger.
+func (l *CompactLogger) Flush(context.Context) {
+	l.emitter.Flush()
+	for _, hook := range l.hooks {
+		hook.Flush()
+	}
+}
+
+var timeNow = time.Now
+
+func (l *CompactLogger) acquireEntry(
+	level types.Level,
+	message string,
+	fields field.AbstractFields,
+	props types.EntryProperties,
+) *types.Entry {
+	entry := l.entryPool.Get().(*types.Entry)
+	entry.Timestamp = timeNow()
+	entry.Level = level
+	entry.Message = message
+	entry.Fields = fields
+	entry.TraceIDs = l.traceIDs
+	entry.Properties = append(entry.Properties, l.entryProperties...)
+	entry.Properties = append(entry.Properties, props...)
+	if l.needsCaller(entry) {
+		entry.Caller = l.getCallerFunc()
+	}
+	return entry
+}
+
+// needsCaller returns true if the caller of the entry is going to be used
+// (by Hooks or by the Emitter). Resolving a caller is expensive, so it
+// is resolved only if it is needed.
+func (l *CompactLogger) needsCaller(entry *types.Entry) bool {
+	if l.getCallerFunc == nil || entry.Caller.Defined() {
+		return false
+	}
+	if len(l.hooks) != 0 {
+		return true
+	}
+	return !entry.Properties.Has(experimental.EntryPropertySkipAllEmitters) && l.emitter.isEnabled(entry.Level)
+}
+
+func (l *CompactLogger) releaseEntry(entry *types.Entry) {
+	entry.Caller = 0
+	entry.Fields = nil
+	entry.Message = ""
+	entry.TraceIDs = nil
+	entry.Properties = entry.Properties[:0]
+	l.entryPool.Put(entry)
+}
+
+func (l *CompactLogger) acquireBuf() *strings.Builder {
+	return l.fmtBufPool.Get().(*strings.Builder)
+}
+
+func (l *CompactLogger) releaseBuf(buf *strings.Builder) {
+	if buf.Cap() > 1024 {
+		return
+	}
+	buf.Reset()
+	l.fmtBufPool.Put(buf)
+}
+
+func (l *CompactLogger) emit(entry *types.Entry) {
+	if len(l.hooks) != 0 && !adapter.ProcessHooks(l.hooks, entry) {
+		return
+	}
+
+	if !entry.Properties.Has(experimental.EntryPropertySkipAllEmitters) {
+		l.prepareEmitter()
+		l.emitter.Emit(entry)
+	}
+
+	// Emitter never panics or exits (see Emitter.Emit), so doing this here:
+	switch entry.Level {
+	case types.LevelPanic:
+		l.Flush(context.TODO())
+		panic(entry.Message)
+	case types.LevelFatal:
+		l.Flush(context.TODO())
+		os.Exit(1)
+	}
+}
+
+// LogFields implements types.CompactLogger.
+func (l *CompactLogger) LogFields(level types.Level, message string, fields field.AbstractFields) {
+	preHooksResult := adapter.LogFieldsPreprocess(l.preHooks, l.traceIDs, level, l.checkLevel(level), message, fields)
+	if preHooksResult.Skip {
+		return
+	}
+
+	if fields != nil {
+		fields = field.ExpandErrors(fields)
+	}
+	if preHooksResult.ExtraFields != nil {
+		fields = field.Add(fields, preHooksResult.ExtraFields)
+	}
+
+	entry := l.acquireEntry(level, l.messagePrefix+message, fields, preHooksResult.ExtraEntryProperties)
+	defer l.releaseEntry(entry)
+	l.emit(entry)
+}
+
+// Logf implements types.CompactLogger.
+func (l *CompactLogger) Logf(level types.Level, format string, args ...any) {
+	preHooksResult := adapter.LogfPreprocess(l.preHooks, l.traceIDs, level, l.checkLevel(level), format, args...)
+	if preHooksResult.Skip {
+		return
+	}
+
+	buf := l.acquireBuf()
+	defer l.releaseBuf(buf)
+	buf.WriteString(l.messagePrefix)
+	fmt.Fprintf(buf, format, args...)
+
+	entry := l.acquireEntry(level, buf.String(), preHooksResult.ExtraFields, preHooksResult.ExtraEntryProperties)
+	defer l.releaseEntry(entry)
+	l.emit(entry)
+}
+
+// Log implements types.CompactLogger.
+func (l *CompactLogger) Log(level types.Level, values ...any) {
+	forceProcess := level == logger.LevelFatal || level == logger.LevelPanic
+
+	preHooksResult := adapter.LogPreprocess(l.preHooks, l.traceIDs, level, l.checkLevel(level), values...)
+	if preHooksResult.Skip && !forceProcess {
+		return
+	}
+
+	if len(values) == 1 {
+		if entry, ok := values[0].(*logger.Entry); ok {
+			if entry.TraceIDs != nil {
+				entry.Fields = field.Add(entry.Fields, preHooksResult.ExtraFields, &field.Field{Key: FieldNameTraceIDs, Value: entry.TraceIDs})
+			} else {
+				entry.Fields = field.Add(entry.Fields, preHooksResult.ExtraFields)
+			}
+			entry.Properties = append(entry.Properties, l.entryProperties...)
+			if l.needsCaller(entry) {
+				entry.Caller = l.getCallerFunc()
+			}
+			l.emit(entry)
+			return
+		}
+	}
+
+	valuesParser := valuesparser.AnySlice(values)
+
+	// parsing the fields also makes valuesParser.WriteUnparsed(buf) work correctly
+	fields := field.Add(field.Gather(&valuesParser), preHooksResult.ExtraFields)
+
+	buf := l.acquireBuf()
+	defer l.releaseBuf(buf)
+	buf.WriteString(l.messagePrefix)
+	valuesParser.WriteUnparsed(buf)
+
+	entry := l.acquireEntry(level, buf.String(), fields, preHooksResult.ExtraEntryProperties)
+	defer l.releaseEntry(entry)
+	l.emit(entry)
+}
+
+func (l *CompactLogger) checkLevel(level types.Level) bool {
+	if l.levelVar != nil && l.levelVar.Level() < level {
+		return false
+	}
+	return l.emitter.CheckLevel(level)
+}
+
+// Level implements types.CompactLogger.
+func (l *CompactLogger) Level() types.Level {
+	maxLevel := types.EndOfLevel - 1
+	if l.levelVar != nil {
+		maxLevel = l.levelVar.Level()
+	}
+	levels := types.Levels()
+	for idx := len(levels) - 1; idx >= 0; idx-- {
+		level := levels[idx]
+		if level <= maxLevel && l.emitter.CheckLevel(level) {
+			return level
+		}
+	}
+	return types.LevelNone
+}
+
+// WithLevel implements types.CompactLogger.
+//
+// The resulting logger is detached from the LevelVar (if it was bound to one).
+func (l *CompactLogger) WithLevel(newLevel types.Level) adapter.CompactLogger {
+	branch := l.branch()
+	branch.levelVar = nil
+	zerologLogger := branch.emitter.ZerologLogger.Level(LevelToZerolog(newLevel))
+	branch.emitter.ZerologLogger = &zerologLogger
+	rootZerologLogger := branch.rootZerologLogger.Level(LevelToZerolog(newLevel))
+	branch.rootZerologLogger = &rootZerologLogger
+	return branch
+}
+
+func (l *CompactLogger) branch() *CompactLogger {
+	// contextNewFields is loaded before the zerolog logger: if the fields
+	// are being compiled concurrently, then we may compile them twice, but
+	// we never lose them.
+	contextNewFields := atomic.LoadUint32(&l.contextNewFields)
+	return &CompactLogger{
+		mostlyPersistentData: l.mostlyPersistentData,
+		emitter:              Emitter{ZerologLogger: l.emitter.getZerologLogger()},
+		levelVar:             l.levelVar,
+		contextFields:        l.contextFields,
+		contextNewFields:     contextNewFields,
+		rootZerologLogger:    l.rootZerologLogger,
+	}
+}
+
+func (l *CompactLogger) clone() *CompactLogger {
+	clone := l.branch()
+	clone.mostlyPersistentData = &[]mostlyPersistentData{*l.mostlyPersistentData}[0]
+	return clone
+}
+
+// WithMessagePrefix implements types.CompactLogger.
+func (l *CompactLogger) WithMessagePrefix(prefix string) adapter.CompactLogger {
+	clone := l.clone()
+	clone.messagePrefix += prefix
+	return clone
+}
+
+// WithEntryProperties implements types.CompactLogger.
+func (l *CompactLogger) WithEntryProperties(props ...types.EntryProperty) adapter.CompactLogger {
+	clone := l.clone()
+	clone.entryProperties = clone.entryProperties.Add(props...)
+	return clone
+}
+
+// WithHooks implements types.CompactLogger.
+func (l *CompactLogger) WithHooks(hooks ...types.Hook) adapter.CompactLogger {
+	clone := l.clone()
+	clone.hooks = make(types.Hooks, len(l.hooks)+len(hooks))
+	copy(clone.hooks, l.hooks)
+	copy(clone.hooks[len(l.hooks):], hooks)
+	if types.Hooks(hooks).HasContextFieldsHook() {
+		// the already compiled context fields have not been processed
+		// by the new hooks, so compiling all of them again:
+		clone.emitter = Emitter{ZerologLogger: clone.rootZerologLogger}
+		clone.contextNewFields = uint32(clone.contextFields.Len())
+	}
+	return clone
+}
+
+// WithPreHooks implements types.CompactLogger.
+func (l *CompactLogger) WithPreHooks(preHooks ...types.PreHook) adapter.CompactLogger {
+	clone := l.clone()
+	clone.preHooks = make(types.PreHooks, len(l.preHooks)+len(preHooks))
+	copy(clone.preHooks, l.preHooks)
+	copy(clone.preHooks[len(l.preHooks):], preHooks)
+	return clone
+}
+
+// WithField implements types.CompactLogger.
+func (l *CompactLogger) WithField(
+	key field.Key,
+	value field.Value,
+	props ...field.Property,
+) adapter.CompactLogger {
+	branch := l.branch()
+	branch.contextFields = l.contextFields.WithField(key, value, props...)
+	branch.contextNewFields++
+	return branch
+}
+
+// WithFields implements types.CompactLogger.
+func (l *CompactLogger) WithFields(fields field.AbstractFields) adapter.CompactLogger {
+	branch := l.branch()
+	if fields == nil {
+		return branch
+	}
+	branch.contextFields = l.contextFields.WithFields(fields)
+	branch.contextNewFields += uint32(fields.Len())
+	return branch
+}
+
+// WithTraceIDs implements types.CompactLogger and belt.Tool.
+func (l *CompactLogger) WithTraceIDs(allTraceIDs belt.TraceIDs, newTraceIDsCount int) belt.Tool {
+	clone := l.clone()
+	clone.traceIDs = allTraceIDs
+	clone.contextFields = clone.contextFields.WithField(FieldNameTraceIDs, allTraceIDs)
+	clone.contextNewFields++
+	return clone
+}
+
+// WithContextFields implements types.CompactLogger and belt.Tool.
+func (l *CompactLogger) WithContextFields(allFields *field.FieldsChain, newFieldsCount int) belt.Tool {
+	branch := l.branch()
+	branch.contextFields = allFields
+	branch.contextNewFields += uint32(newFieldsCount)
+	return branch
+}
+
+func (l *CompactLogger) compileEmitterFields() {
+	// This function is the only one which can change
+	// the value of l.contextNewFields, and it is called
+	// only within l.prepareEmitterOnce.Do, so:
+	// * We do not need to atomically load the l.contextNewFields value.
+	// * We still need to atomically store the l.contextNewFields value,
+	//   because other goroutines may read it concurrently in other functions.
+	if l.contextNewFields == 0 {
+		return
+	}
+	defer atomic.StoreUint32(&l.contextNewFields, 0)
+
+	// FieldsChain provides the newest fields first, and only
+	// the fields which are not compiled yet are needed. They are
+	// added in the reversed order, so that the newest value of
+	// a duplicated key is the last one.
+	newFields := make(field.Fields, 0, l.contextNewFields)
+	l.contextFields.ForEachField(func(f *field.Field) bool {
+		newFields = append(newFields, *f)
+		return len(newFields) < int(l.contextNewFields)
+	})
+	for i, j := 0, len(newFields)-1; i < j; i, j = i+1, j-1 {
+		newFields[i], newFields[j] = newFields[j], newFields[i]
+	}
+	fields := l.hooks.ProcessContextFields(newFields)
+	if fields == nil {
+		return
+	}
+	zerologCtx := l.emitter.ZerologLogger.With()
+	fields.ForEachField(func(f *field.Field) bool {
+		zerologCtx = encodeField(zerologCtx, f)
+		return true
+	})
+	zerologLogger := zerologCtx.Logger()
+	l.emitter.setZerologLogger(&zerologLogger)
+}
+
+func (l *CompactLogger) prepareEmitter() {
+	l.prepareEmitterOnce.Do(func() {
+		l.compileEmitterFields()
+	})
+}
+
+// Emitter implements types.CompactLogger.
+func (l *CompactLogger) Emitter() types.Emitter {
+	l.prepareEmitter()
+	return l.emitter
+}
+
+// DefaultZerologLogger is the (overridable) function which returns
+// a zerolog logger with the default configuration.
+//
+// Do not override this anywhere but in the `main` package.
+var DefaultZerologLogger = func() zerolog.Logger {
+	return zerolog.New(os.Stderr).Level(LevelToZerolog(logger.LevelTrace))
+}
+
+// Default returns a logger.Logger using the default zerolog logger
+// (see DefaultZerologLogger).
+func Default() types.Logger {
+	return New(DefaultZerologLogger())
+}
+
+// New returns a new instance of logger.Logger given a zerolog logger.
+func New(logger zerolog.Logger, opts ...types.Option) types.Logger {
+	return adapter.GenericSugar{
+		CompactLogger: newCompactLoggerFromZerolog(logger, opts...),
+	}
+}
+
+func newCompactLoggerFromZerolog(zerologLogger zerolog.Logger, opts ...types.Option) *CompactLogger {
+	cfg := types.Options(opts).Config()
+	emitter := NewEmitter(zerologLogger)
+	return &CompactLogger{
+		emitter:           emitter,
+		levelVar:          cfg.LevelVar,
+		rootZerologLogger: emitter.ZerologLogger,
+		mostlyPersistentData: &mostlyPersistentData{
+			getCallerFunc: cfg.GetCallerFunc,
+			fmtBufPool: &sync.Pool{
+				New: func() any {
+					return &strings.Builder{}
+				},
+			},
+			entryPool: &sync.Pool{
+				New: func() any {
+					return &types.Entry{}
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package zerolog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/runtime"
+	"github.com/facebookincubator/go-belt/tool/logger/experimental"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type upperCaseHook struct{}
+
+func (upperCaseHook) ProcessLogEntry(entry *types.Entry) bool {
+	entry.Message = strings.ToUpper(entry.Message)
+	return true
+}
+
+func (upperCaseHook) Flush() {}
+
+func newTestLogger(t *testing.T, buf *bytes.Buffer) types.Logger {
+	timeNow = func() time.Time {
+		return time.Date(2022, 2, 24, 0, 0, 0, 0, time.UTC)
+	}
+	t.Cleanup(func() {
+		timeNow = time.Now
+	})
+	return New(zerolog.New(buf), types.OptionGetCallerFunc(nil))
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(t, &buf)
+
+	l.ErrorFields("test", &field.Field{Key: "UserID", Value: 123})
+	require.Equal(t, `{"level":"error","time":"2022-02-24T00:00:00Z","UserID":123,"message":"test"}`, strings.Trim(buf.String(), "\n"))
+	buf.Reset()
+
+	l.Error("test", struct {
+		UserID int `log:"user_id"`
+	}{UserID: 123})
+	require.Equal(t, `{"level":"error","time":"2022-02-24T00:00:00Z","user_id":123,"message":"test"}`, strings.Trim(buf.String(), "\n"))
+	buf.Reset()
+
+	l.Debugf("user %d", 123)
+	require.Equal(t, `{"level":"debug","time":"2022-02-24T00:00:00Z","message":"user 123"}`, strings.Trim(buf.String(), "\n"))
+	buf.Reset()
+}
+
+func TestLoggerContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(t, &buf).WithField("service", "billing")
+
+	child := l.WithFields(field.Fields{
+		{Key: "attempt", Value: 2},
+		{Key: "elapsed", Value: time.Second},
+	})
+	child.Info("charging")
+	require.JSONEq(t, `{"level":"info","service":"billing","attempt":2,"elapsed":1000,"time":"2022-02-24T00:00:00Z","message":"charging"}`, buf.String())
+	buf.Reset()
+
+	// the parent is not affected, and its fields are compiled separately
+	l.WithField("retry", true).Info("charging")
+	require.Equal(t, `{"level":"info","service":"billing","retry":true,"time":"2022-02-24T00:00:00Z","message":"charging"}`, strings.Trim(buf.String(), "\n"))
+	buf.Reset()
+}
+
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(zerolog.New(&buf).Level(zerolog.WarnLevel))
+	require.Equal(t, types.LevelWarning, l.Level())
+
+	l.Info("ignored")
+	require.Empty(t, buf.String())
+
+	l = l.WithLevel(types.LevelDebug)
+	require.Equal(t, types.LevelDebug, l.Level())
+	l.Debug("logged")
+	require.Contains(t, buf.String(), `"message":"logged"`)
+
+	require.Equal(t, types.LevelNone, l.WithLevel(types.LevelNone).Level())
+}
+
+func TestLoggerHooks(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(t, &buf).WithHooks(upperCaseHook{}).WithMessagePrefix("billing: ")
+
+	l.Warn("charging")
+	require.Equal(t, `{"level":"warn","time":"2022-02-24T00:00:00Z","message":"BILLING: CHARGING"}`, strings.Trim(buf.String(), "\n"))
+}
+
+func TestLoggerCallerIsResolvedLazily(t *testing.T) {
+	var buf bytes.Buffer
+	callerCalls := 0
+	l := New(zerolog.New(&buf), types.OptionGetCallerFunc(func() runtime.PC {
+		callerCalls++
+		return runtime.Caller(nil)
+	}))
+
+	l.WithEntryProperties(experimental.EntryPropertySkipAllEmitters).Error("skipped")
+	require.Zero(t, callerCalls)
+	require.Empty(t, buf.String())
+
+	l.Error("emitted")
+	require.Equal(t, 1, callerCalls)
+	require.Contains(t, buf.String(), `"caller":"`)
+	buf.Reset()
+
+	l.WithHooks(upperCaseHook{}).Error("emitted")
+	require.Equal(t, 2, callerCalls)
+	require.Contains(t, buf.String(), `"caller":"`)
+}
+
+func TestLoggerPanic(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(t, &buf)
+
+	require.PanicsWithValue(t, "unable to continue", func() {
+		l.Panic("unable to continue")
+	})
+	require.Contains(t, buf.String(), `"level":"panic"`)
+}
+
+func TestLevelMapping(t *testing.T) {
+	for _, level := range types.Levels() {
+		require.Equal(t, level, LevelFromZerolog(LevelToZerolog(level)))
+	}
+}
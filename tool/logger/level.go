@@ -19,6 +19,25 @@ import (
 // Level is used to define severity of messages to be reported.
 type Level = types.Level
 
+// LevelVar is just a type-alias for logger/types.LevelVar for convenience.
+type LevelVar = types.LevelVar
+
+// NewLevelVar returns a new instance of LevelVar with the given initial level.
+//
+// To bind a Logger to a LevelVar use option types.OptionLevelVar.
+func NewLevelVar(level Level) *LevelVar {
+	return types.NewLevelVar(level)
+}
+
+// LevelDefinition is just a type-alias for logger/types.LevelDefinition for convenience.
+type LevelDefinition = types.LevelDefinition
+
+// RegisterLevel registers a custom level (for example "notice" between
+// LevelWarning and LevelInfo), see types.RegisterLevel.
+func RegisterLevel(def LevelDefinition) error {
+	return types.RegisterLevel(def)
+}
+
 const (
 	// LevelUndefined is the erroneous value of log-level which corresponds
 	// to zero-value.
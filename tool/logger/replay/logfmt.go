@@ -0,0 +1,77 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// parseLogfmt parses a line of `key=value` pairs, where values could be
+// double-quoted (Go-style escaping). A key without a value is reported with value true.
+func parseLogfmt(line []byte, callback func(key string, value any)) error {
+	var hasPairs bool
+	for pos := 0; pos < len(line); {
+		if line[pos] == ' ' || line[pos] == '\t' {
+			pos++
+			continue
+		}
+
+		keyStart := pos
+		for pos < len(line) && line[pos] != '=' && line[pos] != ' ' && line[pos] != '\t' {
+			if line[pos] == '"' {
+				return fmt.Errorf("unexpected quote in a key at position %d", pos)
+			}
+			pos++
+		}
+		key := string(line[keyStart:pos])
+		if pos >= len(line) || line[pos] != '=' {
+			callback(key, true)
+			continue
+		}
+		pos++ // '='
+		if key == "" {
+			return fmt.Errorf("an empty key at position %d", keyStart)
+		}
+		hasPairs = true
+
+		if pos < len(line) && line[pos] == '"' {
+			end := pos + 1
+			for ; end < len(line) && line[end] != '"'; end++ {
+				if line[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(line) {
+				return fmt.Errorf("unterminated quoted value of key '%s'", key)
+			}
+			value, err := strconv.Unquote(string(line[pos : end+1]))
+			if err != nil {
+				return fmt.Errorf("unable to unquote the value of key '%s': %w", key, err)
+			}
+			callback(key, value)
+			pos = end + 1
+			continue
+		}
+
+		valueStart := pos
+		for pos < len(line) && line[pos] != ' ' && line[pos] != '\t' {
+			pos++
+		}
+		callback(key, string(line[valueStart:pos]))
+	}
+	if !hasPairs {
+		return fmt.Errorf("no key=value pairs found")
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+var (
+	// DefaultTimestampKeys are the default keys of the timestamp of an entry
+	// (as used by zap's production and development configs, and by logrus).
+	DefaultTimestampKeys = []string{"ts", "T", "time", "timestamp", "@timestamp"}
+
+	// DefaultLevelKeys are the default keys of the logging level of an entry.
+	DefaultLevelKeys = []string{"level", "L", "lvl", "severity"}
+
+	// DefaultMessageKeys are the default keys of the message of an entry.
+	DefaultMessageKeys = []string{"msg", "M", "message"}
+
+	// DefaultTraceIDsKeys are the default keys of the trace IDs of an entry.
+	DefaultTraceIDsKeys = []string{"trace_id"}
+)
+
+// Option is an optional argument to functions NewReader and Replay.
+type Option interface {
+	apply(*config)
+}
+
+type options []Option
+
+func (s options) Config() config {
+	cfg := config{
+		TimestampKeys: DefaultTimestampKeys,
+		LevelKeys:     DefaultLevelKeys,
+		MessageKeys:   DefaultMessageKeys,
+		TraceIDsKeys:  DefaultTraceIDsKeys,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type config struct {
+	Format        Format
+	TimestampKeys []string
+	LevelKeys     []string
+	MessageKeys   []string
+	TraceIDsKeys  []string
+	Since         time.Time
+	Until         time.Time
+	Level         types.Level
+	KeepPanics    bool
+}
+
+// Format is a format of log lines.
+type Format uint
+
+const (
+	// FormatJSON means JSON objects (one per line).
+	FormatJSON = Format(iota)
+
+	// FormatLogfmt means lines of `key=value` pairs (see https://brandur.org/logfmt),
+	// as produced for example by the logrus' TextFormatter.
+	FormatLogfmt
+
+	// FormatAuto means a line is parsed as JSON if it starts with '{', otherwise as logfmt.
+	FormatAuto
+)
+
+// OptionFormat defines the format of log lines (the default is FormatJSON).
+type OptionFormat Format
+
+func (opt OptionFormat) apply(cfg *config) {
+	cfg.Format = Format(opt)
+}
+
+// OptionTimestampKeys overrides the keys of the timestamp of an entry (see DefaultTimestampKeys).
+type OptionTimestampKeys []string
+
+func (opt OptionTimestampKeys) apply(cfg *config) {
+	cfg.TimestampKeys = opt
+}
+
+// OptionLevelKeys overrides the keys of the logging level of an entry (see DefaultLevelKeys).
+type OptionLevelKeys []string
+
+func (opt OptionLevelKeys) apply(cfg *config) {
+	cfg.LevelKeys = opt
+}
+
+// OptionMessageKeys overrides the keys of the message of an entry (see DefaultMessageKeys).
+type OptionMessageKeys []string
+
+func (opt OptionMessageKeys) apply(cfg *config) {
+	cfg.MessageKeys = opt
+}
+
+// OptionTraceIDsKeys overrides the keys of the trace IDs of an entry (see DefaultTraceIDsKeys).
+type OptionTraceIDsKeys []string
+
+func (opt OptionTraceIDsKeys) apply(cfg *config) {
+	cfg.TraceIDsKeys = opt
+}
+
+// OptionSince makes Replay skip entries with a timestamp before the given one.
+type OptionSince time.Time
+
+func (opt OptionSince) apply(cfg *config) {
+	cfg.Since = time.Time(opt)
+}
+
+// OptionUntil makes Replay skip entries with a timestamp equal to or after the given one.
+type OptionUntil time.Time
+
+func (opt OptionUntil) apply(cfg *config) {
+	cfg.Until = time.Time(opt)
+}
+
+// OptionLevel makes Replay skip entries less severe than the given level.
+type OptionLevel types.Level
+
+func (opt OptionLevel) apply(cfg *config) {
+	cfg.Level = types.Level(opt)
+}
+
+// OptionKeepPanics makes Replay emit entries of levels Panic and Fatal as is,
+// instead of downgrading them to LevelError. Beware: some Emitters panic
+// or exit on such entries.
+type OptionKeepPanics bool
+
+func (opt OptionKeepPanics) apply(cfg *config) {
+	cfg.KeepPanics = bool(opt)
+}
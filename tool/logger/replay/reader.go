@@ -0,0 +1,327 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+// Package replay parses newline-delimited JSON logs (as produced by the JSON
+// encoders of zap and logrus) back into types.Entry values, and re-emits
+// them into any types.Emitter.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+// ParseError is returned by Reader.Read if a line could not be parsed.
+//
+// It does not break the Reader: the next call of Read continues from the next line.
+type ParseError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("unable to parse line %d: %v", err.Line, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+type keyKind uint
+
+const (
+	keyKindField = keyKind(iota)
+	keyKindTimestamp
+	keyKindLevel
+	keyKindMessage
+	keyKindTraceIDs
+)
+
+// Reader reads log entries from newline-delimited JSON (or logfmt, see OptionFormat).
+//
+// The timestamp, the level, the message and the trace IDs are recognized by keys
+// (see DefaultTimestampKeys and the others), all other keys are returned as fields
+// (in the original order).
+//
+// Entry.Caller is never set, since a program counter could not be restored
+// from a log. The caller (if it was logged) is returned as a field.
+type Reader struct {
+	reader *bufio.Reader
+	format Format
+	keys   map[string]keyKind
+	line   int
+}
+
+// NewReader returns a new instance of Reader.
+func NewReader(r io.Reader, opts ...Option) *Reader {
+	cfg := options(opts).Config()
+	keys := map[string]keyKind{}
+	for kind, kindKeys := range map[keyKind][]string{
+		keyKindTimestamp: cfg.TimestampKeys,
+		keyKindLevel:     cfg.LevelKeys,
+		keyKindMessage:   cfg.MessageKeys,
+		keyKindTraceIDs:  cfg.TraceIDsKeys,
+	} {
+		for _, key := range kindKeys {
+			keys[key] = kind
+		}
+	}
+	return &Reader{
+		reader: bufio.NewReader(r),
+		format: cfg.Format,
+		keys:   keys,
+	}
+}
+
+// Read returns the next entry. It returns io.EOF if there are no more entries.
+//
+// Empty lines are skipped. If a line could not be parsed then *ParseError is returned.
+func (r *Reader) Read() (*types.Entry, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return nil, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		entry, parseErr := r.parse(line)
+		if parseErr != nil {
+			return nil, &ParseError{Line: r.line, Text: string(line), Err: parseErr}
+		}
+		return entry, nil
+	}
+}
+
+func (r *Reader) parse(line []byte) (*types.Entry, error) {
+	format := r.format
+	if format == FormatAuto {
+		format = FormatLogfmt
+		if line[0] == '{' {
+			format = FormatJSON
+		}
+	}
+	builder := entryBuilder{
+		Keys:  r.keys,
+		Entry: &types.Entry{},
+	}
+	var err error
+	switch format {
+	case FormatJSON:
+		err = parseJSON(line, builder.Add)
+	case FormatLogfmt:
+		err = parseLogfmt(line, builder.Add)
+	default:
+		err = fmt.Errorf("unknown format %d", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return builder.Result(), nil
+}
+
+func parseJSON(line []byte, callback func(key string, value any)) error {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("not a JSON object")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("unable to decode the value of key '%s': %w", key, err)
+		}
+		callback(key, value)
+	}
+	_, err := decoder.Token()
+	return err
+}
+
+type entryBuilder struct {
+	Keys       map[string]keyKind
+	Entry      *types.Entry
+	Fields     field.Fields
+	HasMessage bool
+}
+
+func (b *entryBuilder) Add(key string, value any) {
+	switch b.Keys[key] {
+	case keyKindTimestamp:
+		if ts, ok := parseTimestamp(value); ok && b.Entry.Timestamp.IsZero() {
+			b.Entry.Timestamp = ts
+			return
+		}
+	case keyKindLevel:
+		if level, ok := parseLevel(value); ok && b.Entry.Level == types.LevelUndefined {
+			b.Entry.Level = level
+			return
+		}
+	case keyKindMessage:
+		if message, ok := value.(string); ok && !b.HasMessage {
+			b.Entry.Message = message
+			b.HasMessage = true
+			return
+		}
+	case keyKindTraceIDs:
+		if traceIDs, ok := parseTraceIDs(value); ok && b.Entry.TraceIDs == nil {
+			b.Entry.TraceIDs = traceIDs
+			return
+		}
+	}
+	b.Fields = append(b.Fields, field.Field{Key: key, Value: normalizeValue(value)})
+}
+
+func (b *entryBuilder) Result() *types.Entry {
+	if b.Fields != nil {
+		b.Entry.Fields = b.Fields
+	}
+	return b.Entry
+}
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700", // zap's ISO8601TimeEncoder
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func parseTimestamp(value any) (time.Time, bool) {
+	switch value := value.(type) {
+	case string:
+		for _, layout := range timestampLayouts {
+			if ts, err := time.Parse(layout, value); err == nil {
+				return ts, true
+			}
+		}
+		return parseEpoch(value)
+	case json.Number:
+		return parseEpoch(value.String())
+	}
+	return time.Time{}, false
+}
+
+// parseEpoch parses a timestamp encoded as the amount of seconds,
+// milliseconds, microseconds or nanoseconds since the epoch (zap may use
+// any of them). They are distinguishable for timestamps after 1973.
+//
+// The string is parsed manually to avoid the float64 precision loss.
+func parseEpoch(s string) (time.Time, bool) {
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	i, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	frac, err := strconv.ParseInt((fracPart + "000000000")[:9], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	abs := i
+	if abs < 0 {
+		abs = -abs
+		frac = -frac
+	}
+	switch {
+	case abs >= 1e17:
+		return time.Unix(0, i), true
+	case abs >= 1e14:
+		return time.UnixMicro(i).Add(time.Duration(frac / 1e6)), true
+	case abs >= 1e11:
+		return time.UnixMilli(i).Add(time.Duration(frac / 1e3)), true
+	}
+	return time.Unix(i, frac), true
+}
+
+func parseLevel(value any) (types.Level, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return types.LevelUndefined, false
+	}
+	if strings.EqualFold(s, "dpanic") {
+		return types.LevelPanic, true
+	}
+	level, err := types.ParseLogLevel(s)
+	if err != nil {
+		return types.LevelUndefined, false
+	}
+	return level, true
+}
+
+func parseTraceIDs(value any) (belt.TraceIDs, bool) {
+	switch value := value.(type) {
+	case string:
+		// a slice formatted by fmt (for example by logrus' TextFormatter): "[id0 id1]"
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			var traceIDs belt.TraceIDs
+			for _, traceID := range strings.Fields(value[1 : len(value)-1]) {
+				traceIDs = append(traceIDs, belt.TraceID(traceID))
+			}
+			return traceIDs, true
+		}
+		return belt.TraceIDs{belt.TraceID(value)}, true
+	case []any:
+		traceIDs := make(belt.TraceIDs, 0, len(value))
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			traceIDs = append(traceIDs, belt.TraceID(s))
+		}
+		return traceIDs, true
+	}
+	return nil, false
+}
+
+// normalizeValue converts json.Number-s into int64 or float64.
+func normalizeValue(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case []any:
+		for idx, item := range value {
+			value[idx] = normalizeValue(item)
+		}
+	case map[string]any:
+		for k, item := range value {
+			value[k] = normalizeValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestReaderZap(t *testing.T) {
+	var buf bytes.Buffer
+	zapLogger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(&buf),
+		zap.DebugLevel,
+	))
+	ts := time.Date(2022, 2, 24, 1, 2, 3, 456000000, time.UTC)
+	zapLogger.Core().Write(zapcore.Entry{Level: zap.WarnLevel, Time: ts, Message: "disk is almost full"}, []zapcore.Field{
+		zap.Int("free_mb", 42), zap.Strings("trace_id", []string{"a", "b"}), zap.Float64("ratio", 0.5),
+	})
+
+	entry, err := NewReader(&buf).Read()
+	require.NoError(t, err)
+	require.Equal(t, types.LevelWarning, entry.Level)
+	require.Equal(t, "disk is almost full", entry.Message)
+	require.True(t, ts.Equal(entry.Timestamp), entry.Timestamp)
+	require.Equal(t, belt.TraceIDs{"a", "b"}, entry.TraceIDs)
+	require.Equal(t, field.Fields{
+		{Key: "free_mb", Value: int64(42)},
+		{Key: "ratio", Value: 0.5},
+	}, entry.Fields)
+
+	_, err = NewReader(&buf).Read()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestReaderLogrus(t *testing.T) {
+	var buf bytes.Buffer
+	logrusLogger := logrus.New()
+	logrusLogger.Out = &buf
+	logrusLogger.Formatter = &logrus.JSONFormatter{}
+	ts := time.Date(2022, 2, 24, 1, 2, 3, 0, time.UTC)
+	logrusLogger.WithTime(ts).WithField("user_id", 1).Error("unable to login")
+
+	entry, err := NewReader(&buf).Read()
+	require.NoError(t, err)
+	require.Equal(t, types.LevelError, entry.Level)
+	require.Equal(t, "unable to login", entry.Message)
+	require.True(t, ts.Equal(entry.Timestamp), entry.Timestamp)
+	require.Equal(t, field.Fields{{Key: "user_id", Value: int64(1)}}, entry.Fields)
+}
+
+func TestReaderInvalidLine(t *testing.T) {
+	r := NewReader(strings.NewReader("not json\n\n{\"msg\":\"ok\",\"ts\":1645664523}\n"))
+	_, err := r.Read()
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, 1, parseErr.Line)
+
+	entry, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, "ok", entry.Message)
+	require.Equal(t, int64(1645664523), entry.Timestamp.Unix())
+	require.Nil(t, entry.Fields)
+}
+
+func TestReaderLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	logrusLogger := logrus.New()
+	logrusLogger.Out = &buf
+	logrusLogger.Formatter = &logrus.TextFormatter{DisableColors: true}
+	ts := time.Date(2022, 2, 24, 1, 2, 3, 0, time.UTC)
+	logrusLogger.WithTime(ts).WithField("user_id", 1).WithField("trace_id", belt.TraceIDs{"a", "b"}).Warn("slow \"request\"")
+
+	for _, format := range []Format{FormatLogfmt, FormatAuto} {
+		entry, err := NewReader(bytes.NewReader(buf.Bytes()), OptionFormat(format)).Read()
+		require.NoError(t, err)
+		require.Equal(t, types.LevelWarning, entry.Level)
+		require.Equal(t, `slow "request"`, entry.Message)
+		require.True(t, ts.Equal(entry.Timestamp), entry.Timestamp)
+		require.Equal(t, belt.TraceIDs{"a", "b"}, entry.TraceIDs)
+		require.Equal(t, field.Fields{{Key: "user_id", Value: "1"}}, entry.Fields)
+	}
+
+	_, err := NewReader(strings.NewReader("just a text line\n"), OptionFormat(FormatAuto)).Read()
+	require.Error(t, err)
+}
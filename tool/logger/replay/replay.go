@@ -0,0 +1,94 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"errors"
+	"io"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+)
+
+var (
+	// FieldNameOriginalLevel is the field name used to provide the original
+	// level of an entry which was downgraded to LevelError by Replay
+	// (see OptionKeepPanics).
+	FieldNameOriginalLevel = "replay.original_level"
+)
+
+// EntryProperty is the type of types.EntryProperty values set by this package.
+type EntryProperty int
+
+const (
+	// EntryPropertyReplayed marks entries emitted by Replay, so that Emitters
+	// and Hooks may distinguish them from entries of the running application.
+	EntryPropertyReplayed = EntryProperty(iota + 1)
+)
+
+// Stats is the summary of a Replay call.
+type Stats struct {
+	// Read is the amount of successfully parsed entries.
+	Read uint
+
+	// Emitted is the amount of entries passed to the Emitter.
+	Emitted uint
+
+	// Invalid is the amount of lines which could not be parsed (they are skipped).
+	Invalid uint
+}
+
+// Replay reads entries from newline-delimited JSON (see Reader) and emits the
+// ones which pass the filters (see OptionSince, OptionUntil and OptionLevel)
+// into the Emitter.
+//
+// Entries of levels Panic and Fatal are emitted with level Error, since
+// some Emitters panic or exit on them (for example the logrus one). Their
+// original level is provided in field FieldNameOriginalLevel. To emit them
+// as is use OptionKeepPanics.
+func Replay(in io.Reader, emitter types.Emitter, opts ...Option) (Stats, error) {
+	cfg := options(opts).Config()
+	reader := NewReader(in, opts...)
+	defer emitter.Flush()
+
+	var stats Stats
+	for {
+		entry, err := reader.Read()
+		if err != nil {
+			var parseErr *ParseError
+			switch {
+			case errors.Is(err, io.EOF):
+				return stats, nil
+			case errors.As(err, &parseErr):
+				stats.Invalid++
+				continue
+			}
+			return stats, err
+		}
+		stats.Read++
+		if !cfg.match(entry) {
+			continue
+		}
+		if !cfg.KeepPanics && entry.Level > types.LevelNone && entry.Level <= types.LevelPanic {
+			entry.Fields = field.Add(entry.Fields, &field.Field{Key: FieldNameOriginalLevel, Value: entry.Level.String()})
+			entry.Level = types.LevelError
+		}
+		entry.Properties = append(entry.Properties, EntryPropertyReplayed)
+		emitter.Emit(entry)
+		stats.Emitted++
+	}
+}
+
+func (cfg *config) match(entry *types.Entry) bool {
+	return types.MatchLevelAndTime(entry.Level, entry.Timestamp, cfg.Level, cfg.Since, cfg.Until)
+}
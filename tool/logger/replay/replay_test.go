@@ -0,0 +1,91 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	"github.com/stretchr/testify/require"
+)
+
+type collectingEmitter struct {
+	Entries []*types.Entry
+	Flushed bool
+}
+
+func (e *collectingEmitter) Emit(entry *types.Entry) {
+	e.Entries = append(e.Entries, entry)
+}
+
+func (e *collectingEmitter) Flush() {
+	e.Flushed = true
+}
+
+func TestReplay(t *testing.T) {
+	const log = `{"level":"info","ts":"2022-02-24T01:00:00Z","msg":"one"}
+{"level":"error","ts":"2022-02-24T02:00:00Z","msg":"two"}
+broken
+{"level":"warn","ts":"2022-02-24T03:00:00Z","msg":"three"}
+{"level":"error","ts":"2022-02-24T04:00:00Z","msg":"four"}
+`
+	var emitter collectingEmitter
+	stats, err := Replay(strings.NewReader(log), &emitter,
+		OptionLevel(types.LevelWarning),
+		OptionSince(time.Date(2022, 2, 24, 2, 0, 0, 0, time.UTC)),
+		OptionUntil(time.Date(2022, 2, 24, 4, 0, 0, 0, time.UTC)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, Stats{Read: 4, Emitted: 2, Invalid: 1}, stats)
+	require.True(t, emitter.Flushed)
+	require.Len(t, emitter.Entries, 2)
+	require.Equal(t, "two", emitter.Entries[0].Message)
+	require.Equal(t, "three", emitter.Entries[1].Message)
+	require.True(t, emitter.Entries[0].Properties.Has(EntryPropertyReplayed))
+}
+
+func TestReplayPanics(t *testing.T) {
+	const log = `{"level":"fatal","msg":"one"}
+{"level":"dpanic","msg":"two","user_id":1}
+{"level":"error","msg":"three"}
+`
+	var emitter collectingEmitter
+	_, err := Replay(strings.NewReader(log), &emitter)
+	require.NoError(t, err)
+	require.Len(t, emitter.Entries, 3)
+	for idx, originalLevel := range []string{"fatal", "panic", ""} {
+		entry := emitter.Entries[idx]
+		require.Equal(t, types.LevelError, entry.Level)
+		if originalLevel == "" {
+			require.Nil(t, entry.Fields)
+			continue
+		}
+		var value any
+		entry.Fields.ForEachField(func(f *field.Field) bool {
+			if f.Key == FieldNameOriginalLevel {
+				value = f.Value
+			}
+			return true
+		})
+		require.Equal(t, originalLevel, value)
+	}
+
+	emitter = collectingEmitter{}
+	_, err = Replay(strings.NewReader(log), &emitter, OptionKeepPanics(true))
+	require.NoError(t, err)
+	require.Equal(t, types.LevelFatal, emitter.Entries[0].Level)
+	require.Equal(t, types.LevelPanic, emitter.Entries[1].Level)
+}
@@ -24,10 +24,15 @@ import (
 
 	"github.com/facebookincubator/go-belt"
 	"github.com/facebookincubator/go-belt/pkg/field"
+	"github.com/facebookincubator/go-belt/pkg/redact"
+	redacthook "github.com/facebookincubator/go-belt/tool/logger/hooks/redact"
+	"github.com/facebookincubator/go-belt/tool/logger/hooks/sanitizer"
 	"github.com/facebookincubator/go-belt/tool/logger/implementation/logrus"
 	"github.com/facebookincubator/go-belt/tool/logger/implementation/stdlib"
 	"github.com/facebookincubator/go-belt/tool/logger/implementation/zap"
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/zerolog"
 	"github.com/facebookincubator/go-belt/tool/logger/types"
+	upstreamzerolog "github.com/rs/zerolog"
 	upstreamlogrus "github.com/sirupsen/logrus"
 	upstreamzap "go.uber.org/zap"
 )
@@ -101,6 +106,16 @@ func getImplementations(t *testing.T) []logger {
 		})
 	}
 
+	// zerolog
+	{
+		var buf bytes.Buffer
+		result = append(result, logger{
+			Name:   "zerolog",
+			Logger: zerolog.New(upstreamzerolog.New(&buf).Level(zerolog.LevelToZerolog(types.LevelTrace))),
+			Output: &buf,
+		})
+	}
+
 	// glog
 	{
 		// the upstream glog logger does not support diverting the output to a buffer
@@ -168,6 +183,85 @@ func TestImplementations(t *testing.T) {
 					t.Fatalf("logger %s did not print the special magic string", l.Name)
 				}
 			})
+
+			t.Run("Logt", func(t *testing.T) {
+				l.Output.Reset()
+				l.Logger.Debugt("user {user_id} logged in from {ip}", 1, "127.0.0.1")
+				l.Logger.WithLevel(types.LevelInfo).Debugt("user {user_id} logged out", 1)
+				l.Logger.Flush(context.TODO())
+				if !strings.Contains(l.Output.String(), "user 1 logged in from 127.0.0.1") {
+					t.Fatalf("logger %s did not print the rendered message: '%s'", l.Name, l.Output.String())
+				}
+				if strings.Contains(l.Output.String(), "logged out") {
+					t.Fatalf("logger %s did not respect the logging level: '%s'", l.Name, l.Output.String())
+				}
+				if l.Name != "stdlib" && !strings.Contains(l.Output.String(), "user {user_id} logged in from {ip}") {
+					t.Fatalf("logger %s did not print the message template: '%s'", l.Name, l.Output.String())
+				}
+			})
+
+			t.Run("redact-context-fields", func(t *testing.T) {
+				if l.Name == "stdlib" {
+					t.Skip("the stdlib logger does not print structured fields")
+				}
+				hook := redacthook.New(redact.New())
+
+				// the fields are already compiled by the time the hook is added:
+				withField := l.Logger.WithField("password", "hunter2")
+				withField.Debug("unit-test-compile")
+
+				l.Output.Reset()
+				withField.WithHooks(hook).Info("unit-test-late-hook")
+				l.Logger.WithHooks(hook).WithField("password", "hunter2").Info("unit-test-Info")
+				l.Logger.WithHooks(hook).WithField("password", "hunter2").Infof("unit-test-Infof")
+				l.Logger.WithHooks(hook).WithField("password", "hunter2").InfoFields("unit-test-InfoFields", field.Fields{{Key: "user_id", Value: 1}})
+				l.Logger.Flush(context.TODO())
+
+				output := l.Output.String()
+				for _, message := range []string{"unit-test-late-hook", "unit-test-Info", "unit-test-Infof", "unit-test-InfoFields"} {
+					if !strings.Contains(output, message) {
+						t.Fatalf("logger %s did not print message '%s': '%s'", l.Name, message, output)
+					}
+				}
+				if strings.Contains(output, "hunter2") {
+					t.Fatalf("logger %s printed a context field which should have been redacted: '%s'", l.Name, output)
+				}
+				if strings.Count(output, "<redacted>") != strings.Count(output, "password") {
+					t.Fatalf("logger %s printed a duplicated context field: '%s'", l.Name, output)
+				}
+			})
+
+			t.Run("sanitize-context-fields", func(t *testing.T) {
+				if l.Name == "stdlib" {
+					t.Skip("the stdlib logger does not print structured fields")
+				}
+				l.Output.Reset()
+				l.Logger.WithHooks(sanitizer.New()).WithField("user", "admin\nINFO fake entry").Info("unit-test")
+				l.Logger.Flush(context.TODO())
+
+				// the escaped value is additionally escaped by the formatter:
+				if !strings.Contains(l.Output.String(), `admin\\nINFO`) {
+					t.Fatalf("logger %s did not escape a context field: '%s'", l.Name, l.Output.String())
+				}
+			})
+
+			t.Run("struct-tags", func(t *testing.T) {
+				if l.Name == "stdlib" {
+					t.Skip("the stdlib logger does not print structured fields")
+				}
+				l.Output.Reset()
+				l.Logger.Info("unit-test", struct {
+					UserID   int    `log:"user_id"`
+					Password string `log:"password,redact"`
+				}{UserID: 1, Password: "secret"})
+				l.Logger.Flush(context.TODO())
+				if !strings.Contains(l.Output.String(), "user_id") || !strings.Contains(l.Output.String(), "<redacted>") {
+					t.Fatalf("logger %s did not print the tagged fields: '%s'", l.Name, l.Output.String())
+				}
+				if strings.Contains(l.Output.String(), "secret") {
+					t.Fatalf("logger %s printed a redacted value: '%s'", l.Name, l.Output.String())
+				}
+			})
 		})
 	}
 }
@@ -0,0 +1,80 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/logrus"
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/stdlib"
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/zap"
+	"github.com/facebookincubator/go-belt/tool/logger/types"
+	upstreamlogrus "github.com/sirupsen/logrus"
+	upstreamzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevelVar(t *testing.T) {
+	newLoggers := map[string]func(*bytes.Buffer, *types.LevelVar) types.Logger{
+		"stdlib": func(buf *bytes.Buffer, levelVar *types.LevelVar) types.Logger {
+			return stdlib.New(log.New(buf, "", 0), types.LevelTrace, types.OptionLevelVar{LevelVar: levelVar})
+		},
+		"zap": func(buf *bytes.Buffer, levelVar *types.LevelVar) types.Logger {
+			zapLogger := upstreamzap.New(zapcore.NewCore(
+				zapcore.NewJSONEncoder(upstreamzap.NewDevelopmentEncoderConfig()),
+				zapcore.AddSync(buf),
+				zap.LevelToZap(types.LevelTrace),
+			))
+			return zap.New(zapLogger, types.OptionLevelVar{LevelVar: levelVar})
+		},
+		"logrus": func(buf *bytes.Buffer, levelVar *types.LevelVar) types.Logger {
+			logrusLogger := upstreamlogrus.New()
+			logrusLogger.Out = buf
+			return logrus.New(logrusLogger, types.OptionLevelVar{LevelVar: levelVar})
+		},
+	}
+
+	for name, newLogger := range newLoggers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			levelVar := types.NewLevelVar(types.LevelInfo)
+			root := newLogger(&buf, levelVar)
+			derived := root.WithField("some-key", "some-value").WithMessagePrefix("prefix: ")
+			detached := derived.WithLevel(types.LevelWarning)
+
+			derived.Debugf("first")
+			levelVar.SetLevel(types.LevelDebug)
+			derived.Debugf("second")
+			detached.Infof("third")
+			derived.Flush(context.Background())
+
+			out := buf.String()
+			if strings.Contains(out, "first") {
+				t.Fatalf("the entry should be filtered out: '%s'", out)
+			}
+			if !strings.Contains(out, "second") {
+				t.Fatalf("the entry should be logged after changing the LevelVar: '%s'", out)
+			}
+			if strings.Contains(out, "third") {
+				t.Fatalf("the detached logger should not follow the LevelVar: '%s'", out)
+			}
+			if derived.Level() != types.LevelDebug {
+				t.Fatalf("unexpected level: %v", derived.Level())
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logger
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger/hooks/throttler"
+)
+
+type throttleKind uint
+
+const (
+	throttleKindUndefined = throttleKind(iota) //nolint:deadcode,unused,varcheck
+	throttleKindEvery
+	throttleKindFirstN
+	throttleKindPer
+)
+
+type throttleKey struct {
+	Kind throttleKind
+	Key  any
+}
+
+// callerPC returns the program counter of the caller of the function which called callerPC.
+func callerPC() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	return pcs[0]
+}
+
+// Every returns a Logger which logs only each n-th entry (starting from the first one)
+// issued through the Logger returned from the same line of code (similar to LOG_EVERY_N of glog).
+//
+// Example:
+//
+//	for _, item := range items {
+//		logger.Every(ctx, 100).Debugf("processing item %v", item)
+//	}
+func Every(ctx context.Context, n uint64) Logger {
+	return EveryByKey(ctx, callerPC(), n)
+}
+
+// EveryByKey is the same as Every, but the budget is shared by the explicitly
+// provided key instead of the line of code.
+func EveryByKey(ctx context.Context, key any, n uint64) Logger {
+	return FromCtx(ctx).WithPreHooks(throttler.GetPreHook(throttleKey{Kind: throttleKindEvery, Key: key}, func() throttler.Limiter {
+		return throttler.NewEvery(n)
+	}))
+}
+
+// FirstN returns a Logger which logs only first n entries issued through the Logger
+// returned from the same line of code (similar to LOG_FIRST_N of glog).
+func FirstN(ctx context.Context, n uint64) Logger {
+	return FirstNByKey(ctx, callerPC(), n)
+}
+
+// FirstNByKey is the same as FirstN, but the budget is shared by the explicitly
+// provided key instead of the line of code.
+func FirstNByKey(ctx context.Context, key any, n uint64) Logger {
+	return FromCtx(ctx).WithPreHooks(throttler.GetPreHook(throttleKey{Kind: throttleKindFirstN, Key: key}, func() throttler.Limiter {
+		return throttler.NewFirstN(n)
+	}))
+}
+
+// Per returns a Logger which logs at most one entry per the interval issued through
+// the Logger returned from the same line of code.
+//
+// Example:
+//
+//	logger.Per(ctx, time.Minute).Warnf("the queue is full")
+func Per(ctx context.Context, interval time.Duration) Logger {
+	return PerByKey(ctx, callerPC(), interval)
+}
+
+// PerByKey is the same as Per, but the budget is shared by the explicitly
+// provided key instead of the line of code.
+func PerByKey(ctx context.Context, key any, interval time.Duration) Logger {
+	return FromCtx(ctx).WithPreHooks(throttler.GetPreHook(throttleKey{Kind: throttleKindPer, Key: key}, func() throttler.Limiter {
+		return throttler.NewPer(interval)
+	}))
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/facebookincubator/go-belt/tool/logger/implementation/stdlib"
+)
+
+func TestThrottle(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := CtxWithLogger(context.Background(), stdlib.New(log.New(&buf, "", 0), LevelTrace))
+
+	for i := 0; i < 10; i++ {
+		Every(ctx, 5).Errorf("every")
+		FirstN(ctx, 3).Errorf("first")
+		Every(ctx, 2).Debugf("another line")
+	}
+
+	out := buf.String()
+	if count := strings.Count(out, "every"); count != 2 {
+		t.Fatalf("expected 2 'every' entries, got %d: %s", count, out)
+	}
+	if count := strings.Count(out, "first"); count != 3 {
+		t.Fatalf("expected 3 'first' entries, got %d: %s", count, out)
+	}
+	if count := strings.Count(out, "another line"); count != 5 {
+		t.Fatalf("expected 5 'another line' entries, got %d: %s", count, out)
+	}
+}
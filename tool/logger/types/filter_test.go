@@ -0,0 +1,34 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchLevelAndTime(t *testing.T) {
+	ts := time.Date(2022, 2, 24, 0, 0, 0, 0, time.UTC)
+
+	require.True(t, MatchLevelAndTime(LevelDebug, ts, LevelUndefined, time.Time{}, time.Time{}))
+	require.True(t, MatchLevelAndTime(LevelError, ts, LevelWarning, time.Time{}, time.Time{}))
+	require.True(t, MatchLevelAndTime(LevelWarning, ts, LevelWarning, time.Time{}, time.Time{}))
+	require.False(t, MatchLevelAndTime(LevelInfo, ts, LevelWarning, time.Time{}, time.Time{}))
+	require.False(t, MatchLevelAndTime(LevelNone, ts, LevelWarning, time.Time{}, time.Time{}))
+
+	require.True(t, MatchLevelAndTime(LevelInfo, ts, LevelUndefined, ts, ts.Add(time.Second)))
+	require.False(t, MatchLevelAndTime(LevelInfo, ts, LevelUndefined, ts.Add(time.Nanosecond), time.Time{}))
+	require.False(t, MatchLevelAndTime(LevelInfo, ts, LevelUndefined, time.Time{}, ts))
+}
@@ -32,10 +32,28 @@ type Hook interface {
 	Flush()
 }
 
+// ContextFieldsHook is an optional interface of a Hook, which allows to
+// process the context fields (see Logger.WithField and Logger.WithFields).
+//
+// Some Logger implementations (for example the zap, logrus and zerolog ones)
+// compile the context fields into the backend logger, so modifying these
+// fields in ProcessLogEntry has no effect on the output (and the zerolog one
+// does not pass them through Entry.Fields at all). Such implementations pass
+// the context fields through ProcessContextFields right before compiling them.
+type ContextFieldsHook interface {
+	Hook
+
+	// ProcessContextFields returns the fields to be compiled instead of the given ones.
+	ProcessContextFields(field.AbstractFields) field.AbstractFields
+}
+
 // Hooks is a collection of Hook-s.
 type Hooks []Hook
 
-var _ Hook = (Hooks)(nil)
+var (
+	_ Hook              = (Hooks)(nil)
+	_ ContextFieldsHook = (Hooks)(nil)
+)
 
 // ProcessLogEntry implements Hook.
 func (s Hooks) ProcessLogEntry(e *Entry) bool {
@@ -54,6 +72,31 @@ func (s Hooks) Flush() {
 	}
 }
 
+// ProcessContextFields implements ContextFieldsHook.
+func (s Hooks) ProcessContextFields(fields field.AbstractFields) field.AbstractFields {
+	for _, hook := range s {
+		if hook, ok := hook.(ContextFieldsHook); ok {
+			fields = hook.ProcessContextFields(fields)
+		}
+	}
+	return fields
+}
+
+// HasContextFieldsHook returns true if any of the hooks implements ContextFieldsHook.
+func (s Hooks) HasContextFieldsHook() bool {
+	for _, hook := range s {
+		switch hook := hook.(type) {
+		case Hooks:
+			if hook.HasContextFieldsHook() {
+				return true
+			}
+		case ContextFieldsHook:
+			return true
+		}
+	}
+	return false
+}
+
 // PreHookResult is a result of computations returned by a PreHook.
 type PreHookResult struct {
 	// Skip forces a Logger to do not log this Entry.
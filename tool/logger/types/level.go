@@ -31,12 +31,24 @@ import (
 // There are two ways to use Level:
 // 1. To define a severity of a specific message/Entry (when logging).
 // 2. To define a severity of messages/Entries be actually logged (when configuring a Logger).
+//
+// The numeric values of the built-in levels are LevelStep apart from each
+// other, the gaps are reserved for custom levels (see RegisterLevel).
+//
+// Breaking change: previously the built-in levels had sequential numeric values
+// (LevelUndefined was 0, LevelNone was 1, ..., LevelTrace was 8). If a Level
+// is persisted outside of the process, prefer its name (see Level.String and
+// ParseLogLevel); a numeric value persisted in the old format should be
+// multiplied by LevelStep.
 type Level int
 
+// LevelStep is the difference between numeric values of two adjacent built-in levels.
+const LevelStep = 16
+
 const (
 	// LevelUndefined is an erroneous value of log-level which just corresponds
 	// to the zero-value.
-	LevelUndefined = Level(iota)
+	LevelUndefined = Level(iota * LevelStep)
 
 	// LevelNone means to do not log anything.
 	//
@@ -98,7 +110,7 @@ const (
 	// A message with this level is just logged if the Logger is setup with level no less that this.
 	LevelTrace
 
-	// EndOfLevel just defines a maximum level + 1.
+	// EndOfLevel just defines an upper (exclusive) bound of levels.
 	EndOfLevel
 )
 
@@ -123,6 +135,9 @@ func (logLevel Level) Byte() byte {
 	case LevelFatal:
 		return 'F'
 	}
+	if def, ok := LookupLevel(logLevel); ok && def.Byte != 0 {
+		return def.Byte
+	}
 	return 'U'
 }
 
@@ -148,6 +163,9 @@ func (logLevel Level) String() string {
 	case LevelFatal:
 		return "fatal"
 	}
+	if def, ok := LookupLevel(logLevel); ok {
+		return def.Name
+	}
 	return fmt.Sprintf("unknown_%d", logLevel)
 }
 
@@ -188,8 +206,11 @@ func ParseLogLevel(in string) (Level, error) {
 	case "n", "none":
 		return LevelNone, nil
 	}
+	if logLevel, ok := levelByName(in); ok {
+		return logLevel, nil
+	}
 	var allowedValues []string
-	for logLevel := LevelFatal; logLevel <= LevelDebug; logLevel++ {
+	for _, logLevel := range Levels() {
 		allowedValues = append(allowedValues, logLevel.String())
 	}
 	return LevelUndefined, fmt.Errorf("unknown logging level '%s', known values are: %s",
@@ -0,0 +1,267 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelDefinition describes a custom Level (see RegisterLevel).
+type LevelDefinition struct {
+	// Level is the numeric value of the level, which defines its ordering
+	// relatively to other levels (a higher value means a more verbose level).
+	//
+	// It is supposed to be between built-in levels after LevelPanic, for example
+	// `LevelInfo - LevelStep/2` is more severe than Info, but less severe than Warning.
+	// Levels as severe as Panic or more are not allowed, because backends
+	// treat them as panic or fatal levels.
+	Level Level
+
+	// Name is returned by Level.String and is accepted by ParseLogLevel.
+	Name string
+
+	// Aliases are additional names accepted by ParseLogLevel.
+	Aliases []string
+
+	// Byte is returned by Level.Byte.
+	Byte byte
+
+	// Fallback is the built-in level used by Emitters which have no specific mapping
+	// of this level. If it is not set then the closest more verbose built-in level is
+	// used (which guarantees a message is not dropped by a backend configured
+	// with this level). It could not be LevelPanic or LevelFatal.
+	Fallback Level
+}
+
+type levelRegistry struct {
+	ByLevel map[Level]*LevelDefinition
+	ByName  map[string]Level
+	Sorted  []Level
+}
+
+var (
+	levelRegistryLocker  sync.Mutex
+	levelRegistryPointer atomic.Pointer[levelRegistry]
+)
+
+// RegisterLevel registers a custom Level, making Level.String, Level.Byte
+// and ParseLogLevel aware of it.
+//
+// Backend-specific mappings could be registered by the respective
+// implementations (for example see RegisterSyslogSeverity). Custom levels
+// are not supposed to be mapped to panic or fatal levels of backends, thus
+// only levels less severe than LevelPanic could be registered. A level
+// between LevelPanic and LevelError (for example "critical") falls back
+// to LevelError.
+//
+// It is supposed to be called on the initialization of an application,
+// before the level is used.
+func RegisterLevel(def LevelDefinition) error {
+	if def.Level <= LevelPanic || def.Level >= EndOfLevel {
+		return fmt.Errorf("level %d is out of range (%d, %d)", def.Level, LevelPanic, EndOfLevel)
+	}
+	if def.Level.IsBuiltin() {
+		return fmt.Errorf("level %d is a built-in level '%s'", def.Level, def.Level)
+	}
+	if def.Fallback == LevelUndefined {
+		def.Fallback = def.Level.Builtin()
+	}
+	if !def.Fallback.IsBuiltin() || def.Fallback <= LevelPanic {
+		return fmt.Errorf("fallback level %d is not a non-panicking built-in level", def.Fallback)
+	}
+	if def.Name == "" {
+		return fmt.Errorf("the name of level %d is empty", def.Level)
+	}
+	def.Aliases = append([]string{}, def.Aliases...)
+
+	levelRegistryLocker.Lock()
+	defer levelRegistryLocker.Unlock()
+
+	old := levelRegistryPointer.Load()
+	if old == nil {
+		old = &levelRegistry{}
+	}
+	if _, ok := old.ByLevel[def.Level]; ok {
+		return fmt.Errorf("level %d is already registered as '%s'", def.Level, def.Level)
+	}
+
+	registry := &levelRegistry{
+		ByLevel: make(map[Level]*LevelDefinition, len(old.ByLevel)+1),
+		ByName:  make(map[string]Level, len(old.ByName)+len(def.Aliases)+1),
+		Sorted:  append([]Level{def.Level}, old.Sorted...),
+	}
+	for k, v := range old.ByLevel {
+		registry.ByLevel[k] = v
+	}
+	for k, v := range old.ByName {
+		registry.ByName[k] = v
+	}
+	for _, name := range append([]string{def.Name}, def.Aliases...) {
+		name = strings.ToLower(name)
+		if existing, err := ParseLogLevel(name); err == nil {
+			return fmt.Errorf("name '%s' is already used by level '%s'", name, existing)
+		}
+		registry.ByName[name] = def.Level
+	}
+	registry.ByLevel[def.Level] = &def
+	sort.Slice(registry.Sorted, func(i, j int) bool {
+		return registry.Sorted[i] < registry.Sorted[j]
+	})
+
+	levelRegistryPointer.Store(registry)
+	return nil
+}
+
+// MustRegisterLevel is the same as RegisterLevel, but panics on an error
+// and returns the registered Level.
+//
+// Example:
+//
+//	var LevelNotice = types.MustRegisterLevel(types.LevelDefinition{
+//		Level: types.LevelInfo - types.LevelStep/2,
+//		Name:  "notice",
+//		Byte:  'N',
+//	})
+func MustRegisterLevel(def LevelDefinition) Level {
+	if err := RegisterLevel(def); err != nil {
+		panic(err)
+	}
+	return def.Level
+}
+
+// LookupLevel returns the definition of a custom Level registered by RegisterLevel.
+func LookupLevel(level Level) (LevelDefinition, bool) {
+	registry := levelRegistryPointer.Load()
+	if registry == nil {
+		return LevelDefinition{}, false
+	}
+	def, ok := registry.ByLevel[level]
+	if !ok {
+		return LevelDefinition{}, false
+	}
+	return *def, true
+}
+
+func levelByName(name string) (Level, bool) {
+	registry := levelRegistryPointer.Load()
+	if registry == nil {
+		return LevelUndefined, false
+	}
+	level, ok := registry.ByName[strings.ToLower(name)]
+	return level, ok
+}
+
+// Levels returns all the known levels (built-in and registered ones)
+// which could be used to log a message, in the order from Fatal to Trace.
+func Levels() []Level {
+	var custom []Level
+	if registry := levelRegistryPointer.Load(); registry != nil {
+		custom = registry.Sorted
+	}
+	result := make([]Level, 0, int(LevelTrace-LevelFatal)/LevelStep+1+len(custom))
+	for level := LevelFatal; level <= LevelTrace; level += LevelStep {
+		for len(custom) > 0 && custom[0] < level {
+			result = append(result, custom[0])
+			custom = custom[1:]
+		}
+		result = append(result, level)
+	}
+	return append(result, custom...)
+}
+
+// IsBuiltin returns true if the level is one of the levels defined in this package.
+func (logLevel Level) IsBuiltin() bool {
+	return logLevel >= LevelUndefined && logLevel < EndOfLevel && logLevel%LevelStep == 0
+}
+
+// Builtin returns the built-in level to be used instead of this level
+// by Emitters, which have no specific mapping for it.
+//
+// A built-in level is returned as is.
+func (logLevel Level) Builtin() Level {
+	switch {
+	case logLevel.IsBuiltin():
+		return logLevel
+	case logLevel < LevelUndefined:
+		return LevelUndefined
+	case logLevel > LevelTrace:
+		return LevelTrace
+	}
+	if def, ok := LookupLevel(logLevel); ok {
+		return def.Fallback
+	}
+	return (logLevel/LevelStep + 1) * LevelStep
+}
+
+// LevelMapping is a concurrency-safe mapping of custom levels to levels of
+// a specific backend (syslog, zap, logrus, etc), and vice versa.
+//
+// The zero value is ready to be used.
+type LevelMapping[T comparable] struct {
+	locker  sync.Mutex
+	pointer atomic.Pointer[levelMappingData[T]]
+}
+
+type levelMappingData[T comparable] struct {
+	To   map[Level]T
+	From map[T]Level
+}
+
+// Set sets the backend level for the given level. The backward mapping
+// (see method From) is set as well.
+func (m *LevelMapping[T]) Set(level Level, backendLevel T) {
+	m.locker.Lock()
+	defer m.locker.Unlock()
+	old := m.pointer.Load()
+	if old == nil {
+		old = &levelMappingData[T]{}
+	}
+	data := &levelMappingData[T]{
+		To:   make(map[Level]T, len(old.To)+1),
+		From: make(map[T]Level, len(old.From)+1),
+	}
+	for k, v := range old.To {
+		data.To[k] = v
+	}
+	for k, v := range old.From {
+		data.From[k] = v
+	}
+	data.To[level] = backendLevel
+	data.From[backendLevel] = level
+	m.pointer.Store(data)
+}
+
+// To returns the backend level set for the given level.
+func (m *LevelMapping[T]) To(level Level) (T, bool) {
+	data := m.pointer.Load()
+	if data == nil {
+		var zeroValue T
+		return zeroValue, false
+	}
+	backendLevel, ok := data.To[level]
+	return backendLevel, ok
+}
+
+// From returns the level which was set for the given backend level.
+func (m *LevelMapping[T]) From(backendLevel T) (Level, bool) {
+	data := m.pointer.Load()
+	if data == nil {
+		return LevelUndefined, false
+	}
+	level, ok := data.From[backendLevel]
+	return level, ok
+}
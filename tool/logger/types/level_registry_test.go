@@ -0,0 +1,93 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testLevelNotice = MustRegisterLevel(LevelDefinition{
+		Level: LevelInfo - LevelStep/2,
+		Name:  "notice",
+		Byte:  'N',
+	})
+	testLevelCritical = MustRegisterLevel(LevelDefinition{
+		Level:   LevelError - LevelStep/2,
+		Name:    "critical",
+		Aliases: []string{"crit"},
+		Byte:    'C',
+	})
+)
+
+func init() {
+	RegisterSyslogSeverity(testLevelNotice, SyslogSeverityNotice)
+	RegisterSyslogSeverity(testLevelCritical, SyslogSeverityCritical)
+}
+
+func TestRegisterLevel(t *testing.T) {
+	require.True(t, LevelWarning < testLevelNotice && testLevelNotice < LevelInfo)
+	require.True(t, LevelPanic < testLevelCritical && testLevelCritical < LevelError)
+
+	require.Equal(t, "notice", testLevelNotice.String())
+	require.Equal(t, byte('C'), testLevelCritical.Byte())
+
+	for in, expected := range map[string]Level{
+		"notice":   testLevelNotice,
+		"NOTICE":   testLevelNotice,
+		"critical": testLevelCritical,
+		"crit":     testLevelCritical,
+		"warn":     LevelWarning,
+	} {
+		level, err := ParseLogLevel(in)
+		require.NoError(t, err, in)
+		require.Equal(t, expected, level, in)
+	}
+	_, err := ParseLogLevel("emergency")
+	require.ErrorContains(t, err, "critical")
+
+	require.Equal(t, []Level{
+		LevelFatal, LevelPanic, testLevelCritical, LevelError,
+		LevelWarning, testLevelNotice, LevelInfo, LevelDebug, LevelTrace,
+	}, Levels())
+
+	require.Equal(t, LevelInfo, testLevelNotice.Builtin())
+	require.Equal(t, LevelError, testLevelCritical.Builtin())
+	require.Equal(t, LevelWarning, LevelWarning.Builtin())
+
+	// errors
+	require.Error(t, RegisterLevel(LevelDefinition{Level: testLevelNotice, Name: "notice2"}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: testLevelNotice + 1, Name: "crit"}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: testLevelNotice + 1, Name: "info"}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: LevelDebug, Name: "verbose"}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: LevelNone + 1, Name: "verbose"}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: LevelFatal + LevelStep/2, Name: "verbose"}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: LevelPanic, Name: "verbose"}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: LevelError - 1, Name: "verbose", Fallback: LevelPanic}))
+	require.Error(t, RegisterLevel(LevelDefinition{Level: testLevelNotice + 1}))
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	require.Equal(t, SyslogSeverityNotice, LevelToSyslogSeverity(testLevelNotice))
+	require.Equal(t, SyslogSeverityCritical, LevelToSyslogSeverity(testLevelCritical))
+	require.Equal(t, SyslogSeverityWarning, LevelToSyslogSeverity(LevelWarning))
+	require.Equal(t, SyslogSeverityDebug, LevelToSyslogSeverity(LevelTrace))
+	require.Equal(t, SyslogSeverityInformational, LevelToSyslogSeverity(LevelInfo-1))
+	require.Equal(t, SyslogSeverityCritical, LevelToSyslogSeverity(LevelError-1))
+
+	require.Equal(t, testLevelNotice, LevelFromSyslogSeverity(SyslogSeverityNotice))
+	require.Equal(t, testLevelCritical, LevelFromSyslogSeverity(SyslogSeverityCritical))
+	require.Equal(t, LevelInfo, LevelFromSyslogSeverity(SyslogSeverityInformational))
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// LevelVar is an atomically updatable Level, which could be shared by
+// multiple Loggers (see OptionLevelVar). Changing the LevelVar instantly
+// changes the logging level of all the Loggers bound to it (including
+// all the Loggers derived from them).
+//
+// It also implements http.Handler to read/change the level at runtime:
+// GET returns the current level as `{"level":"info"}` and PUT (or POST)
+// changes the level given a JSON body in the same format or given
+// a form/query value "level".
+//
+// The zero value is LevelUndefined (thus nothing is logged), use NewLevelVar
+// or SetLevel to initialize it.
+type LevelVar struct {
+	level int64
+}
+
+var _ http.Handler = (*LevelVar)(nil)
+
+// NewLevelVar returns a new instance of LevelVar with the given initial level.
+func NewLevelVar(level Level) *LevelVar {
+	v := &LevelVar{}
+	v.SetLevel(level)
+	return v
+}
+
+// Level returns the current logging level.
+func (v *LevelVar) Level() Level {
+	return Level(atomic.LoadInt64(&v.level))
+}
+
+// SetLevel changes the logging level.
+func (v *LevelVar) SetLevel(level Level) {
+	atomic.StoreInt64(&v.level, int64(level))
+}
+
+// String implements fmt.Stringer.
+func (v *LevelVar) String() string {
+	return v.Level().String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v *LevelVar) MarshalText() ([]byte, error) {
+	return []byte(v.Level().String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *LevelVar) UnmarshalText(text []byte) error {
+	level, err := ParseLogLevel(string(text))
+	if err != nil {
+		return err
+	}
+	v.SetLevel(level)
+	return nil
+}
+
+type levelVarPayload struct {
+	Level *LevelVar `json:"level"`
+}
+
+type levelVarErrorPayload struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements http.Handler.
+func (v *LevelVar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		level, err := levelFromRequest(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = enc.Encode(levelVarErrorPayload{Error: err.Error()})
+			return
+		}
+		v.SetLevel(level)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = enc.Encode(levelVarErrorPayload{Error: fmt.Sprintf("method %s is not supported, only GET, PUT and POST are allowed", r.Method)})
+		return
+	}
+
+	_ = enc.Encode(levelVarPayload{Level: v})
+}
+
+func levelFromRequest(r *http.Request) (Level, error) {
+	if value := r.FormValue("level"); value != "" {
+		return ParseLogLevel(value)
+	}
+
+	var payload levelVarPayload
+	payload.Level = &LevelVar{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return LevelUndefined, fmt.Errorf("unable to parse the request body: %w", err)
+	}
+	level := payload.Level.Level()
+	if level == LevelUndefined {
+		return LevelUndefined, fmt.Errorf("the level is not specified")
+	}
+	return level, nil
+}
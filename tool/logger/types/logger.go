@@ -108,6 +108,20 @@ type Logger interface {
 	// 	l.Log(logger.LevelDebug, "current user ID is ", user.ID, " and group ID is ", user.GroupID) // will result into message "current user ID is 1234 and group ID is 5678".
 	Log(level Level, values ...any)
 
+	// Logt logs a message defined by a template with named placeholders,
+	// like "user {user_id} logged in from {ip}".
+	//
+	// The arguments are assigned to the placeholders positionally and
+	// are both rendered into the message and logged as structured fields.
+	// The template itself is logged as field "message_template", so
+	// it could be used to group messages regardless of the values.
+	// See messagetemplate.Template.Execute for details.
+	//
+	// Examples:
+	//
+	// 	l.Logt(logger.LevelInfo, "user {user_id} logged in from {ip}", user.ID, remoteAddr)
+	Logt(level Level, template string, args ...any)
+
 	// Emitter returns the Emitter (see the description of interface "Emitter").
 	Emitter() Emitter
 
@@ -189,6 +203,31 @@ type Logger interface {
 	// Be aware: Fatal level also triggers an `os.Exit`.
 	Fatalf(format string, args ...any)
 
+	// Tracet is just a shorthand for Logt(logger.LevelTrace, ...)
+	Tracet(template string, args ...any)
+
+	// Debugt is just a shorthand for Logt(logger.LevelDebug, ...)
+	Debugt(template string, args ...any)
+
+	// Infot is just a shorthand for Logt(logger.LevelInfo, ...)
+	Infot(template string, args ...any)
+
+	// Warnt is just a shorthand for Logt(logger.LevelWarn, ...)
+	Warnt(template string, args ...any)
+
+	// Errort is just a shorthand for Logt(logger.LevelError, ...)
+	Errort(template string, args ...any)
+
+	// Panict is just a shorthand for Logt(logger.LevelPanic, ...)
+	//
+	// Be aware: Panic level also triggers a `panic`.
+	Panict(template string, args ...any)
+
+	// Fatalt is just a shorthand for Logt(logger.LevelFatal, ...)
+	//
+	// Be aware: Fatal level also triggers an `os.Exit`.
+	Fatalt(template string, args ...any)
+
 	// WithLevel returns a logger with logger level set to the given argument.
 	//
 	// See also the description of type "Level".
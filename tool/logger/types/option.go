@@ -37,6 +37,10 @@ type Config struct {
 	// see the description of "GetCallerPC" .
 	GetCallerFunc GetCallerPC
 
+	// LevelVar is the shared logging level the Logger should be bound to,
+	// see the description of "LevelVar".
+	LevelVar *LevelVar
+
 	// ImplementationSpecificOptions is a set of Logger-implementation-specific
 	// options.
 	ImplementationSpecificOptions []any
@@ -48,3 +52,17 @@ type OptionGetCallerFunc GetCallerPC
 func (opt OptionGetCallerFunc) apply(cfg *Config) {
 	cfg.GetCallerFunc = GetCallerPC(opt)
 }
+
+// OptionLevelVar binds the Logger to the given LevelVar, so the logging
+// level of the Logger (and of all Loggers derived from it) follows the
+// value of the LevelVar.
+//
+// A Logger derived through method WithLevel is detached from the LevelVar
+// and uses the fixed logging level passed to WithLevel.
+type OptionLevelVar struct {
+	LevelVar *LevelVar
+}
+
+func (opt OptionLevelVar) apply(cfg *Config) {
+	cfg.LevelVar = opt.LevelVar
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Meta Platforms, Inc. and affiliates.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package types
+
+import (
+	"fmt"
+)
+
+// SyslogSeverity is a severity of a message as defined in RFC 5424 (section 6.2.1).
+type SyslogSeverity uint8
+
+const (
+	// SyslogSeverityEmergency means the system is unusable.
+	SyslogSeverityEmergency = SyslogSeverity(iota)
+
+	// SyslogSeverityAlert means an action must be taken immediately.
+	SyslogSeverityAlert
+
+	// SyslogSeverityCritical means critical conditions.
+	SyslogSeverityCritical
+
+	// SyslogSeverityError means error conditions.
+	SyslogSeverityError
+
+	// SyslogSeverityWarning means warning conditions.
+	SyslogSeverityWarning
+
+	// SyslogSeverityNotice means normal but significant conditions.
+	SyslogSeverityNotice
+
+	// SyslogSeverityInformational means informational messages.
+	SyslogSeverityInformational
+
+	// SyslogSeverityDebug means debug-level messages.
+	SyslogSeverityDebug
+)
+
+var syslogLevelMapping LevelMapping[SyslogSeverity]
+
+// RegisterSyslogSeverity sets the syslog severity to be used for a custom level.
+func RegisterSyslogSeverity(level Level, severity SyslogSeverity) {
+	syslogLevelMapping.Set(level, severity)
+}
+
+// LevelToSyslogSeverity converts Level to SyslogSeverity.
+//
+// Custom levels between LevelPanic and LevelError without a registered
+// severity are converted to SyslogSeverityCritical.
+func LevelToSyslogSeverity(level Level) SyslogSeverity {
+	switch level {
+	case LevelTrace, LevelDebug:
+		return SyslogSeverityDebug
+	case LevelInfo:
+		return SyslogSeverityInformational
+	case LevelWarning:
+		return SyslogSeverityWarning
+	case LevelError:
+		return SyslogSeverityError
+	case LevelPanic:
+		return SyslogSeverityAlert
+	case LevelFatal:
+		return SyslogSeverityEmergency
+	}
+	if severity, ok := syslogLevelMapping.To(level); ok {
+		return severity
+	}
+	if level > LevelPanic && level < LevelError {
+		return SyslogSeverityCritical
+	}
+	if builtin := level.Builtin(); builtin != level && builtin > LevelNone {
+		return LevelToSyslogSeverity(builtin)
+	}
+	panic(fmt.Errorf("unexpected level: %v", level))
+}
+
+// LevelFromSyslogSeverity converts SyslogSeverity to Level.
+//
+// Severities Critical and Notice are converted to custom levels if
+// they were registered through RegisterSyslogSeverity.
+func LevelFromSyslogSeverity(severity SyslogSeverity) Level {
+	switch severity {
+	case SyslogSeverityEmergency:
+		return LevelFatal
+	case SyslogSeverityAlert:
+		return LevelPanic
+	case SyslogSeverityError:
+		return LevelError
+	case SyslogSeverityWarning:
+		return LevelWarning
+	case SyslogSeverityInformational:
+		return LevelInfo
+	case SyslogSeverityDebug:
+		return LevelDebug
+	}
+	if level, ok := syslogLevelMapping.From(severity); ok {
+		return level
+	}
+	switch severity {
+	case SyslogSeverityCritical:
+		return LevelError
+	case SyslogSeverityNotice:
+		return LevelInfo
+	}
+	panic(fmt.Errorf("unexpected syslog severity: %d", severity))
+}